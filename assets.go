@@ -0,0 +1,61 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/render"
+)
+
+// assets holds the default templates and static files, so the binary can build
+// a site without a checkout of the repository.
+//
+//go:embed templates static
+var assets embed.FS
+
+// templateLayers returns the embedded templates followed by the templates
+// directory, the theme and the overrides. The templates directory is optional,
+// a configured theme or overrides directory has to exist.
+func templateLayers(paths config.Paths) ([]render.TemplateLayer, error) {
+	templates, err := fs.Sub(assets, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []render.TemplateLayer{{Name: "embedded", FS: templates}}
+
+	if isDir(paths.Templates) {
+		layers = append(layers, render.TemplateLayer{Name: "templates", FS: os.DirFS(paths.Templates)})
+	}
+	if paths.Theme != "" {
+		layers = append(layers, render.TemplateLayer{Name: "theme", FS: os.DirFS(paths.Theme)})
+	}
+	if paths.Overrides != "" {
+		layers = append(layers, render.TemplateLayer{Name: "overrides", FS: os.DirFS(paths.Overrides)})
+	}
+
+	return layers, nil
+}
+
+// staticFiles returns the embedded static files followed by the static
+// directory if it exists, files in the directory replace the embedded ones.
+func staticFiles(paths config.Paths) ([]fs.FS, error) {
+	static, err := fs.Sub(assets, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []fs.FS{static}
+	if isDir(paths.Static) {
+		files = append(files, os.DirFS(paths.Static))
+	}
+
+	return files, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/pages"
+	"bookshelf/internal/render"
+	"bookshelf/internal/storage"
+)
+
+// build renders the site into a staging directory which replaces the output
+// path once all pages are rendered. A failed build leaves the output of the
+// last build untouched. Private and draft books are only rendered with
+// includePrivate, e.g. for a local preview, into the directory returned by
+// buildOutputPath.
+func build(siteConfig config.Config, workers int, includePrivate bool) (render.BuildSummary, error) {
+	siteConfig.Paths.Output = buildOutputPath(siteConfig.Paths, includePrivate)
+
+	var bookshelf *dto.Bookshelf
+	var shelves []dto.Shelf
+	var err error
+	if len(siteConfig.Shelves) > 0 {
+		shelves, err = loadShelves(siteConfig.Shelves)
+	} else {
+		bookshelf, err = storage.Load(siteConfig.Paths.Data)
+	}
+	if err != nil {
+		return render.BuildSummary{}, err
+	}
+
+	for i := range shelves {
+		shelves[i].Bookshelf = visible(shelves[i].Bookshelf, includePrivate)
+	}
+	if bookshelf != nil {
+		bookshelf = visible(bookshelf, includePrivate)
+	}
+
+	// The pages of each shelf are dated by the shelf itself
+	lastUpdated := ""
+	if bookshelf != nil {
+		lastUpdated = bookshelf.LastModified()
+	}
+
+	layers, err := templateLayers(siteConfig.Paths)
+	if err != nil {
+		return render.BuildSummary{}, fmt.Errorf("loading templates: %w", err)
+	}
+
+	static, err := staticFiles(siteConfig.Paths)
+	if err != nil {
+		return render.BuildSummary{}, fmt.Errorf("loading static files: %w", err)
+	}
+
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         layers,
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             siteConfig.Paths.Output,
+		BaseTemplateName:       "base",
+		Site:                   siteConfig.Site,
+		Features:               siteConfig.Features,
+		BookshelfPage:          siteConfig.BookshelfPage,
+		Workers:                workers,
+		LastUpdated:            lastUpdated,
+	})
+	if err != nil {
+		return render.BuildSummary{}, fmt.Errorf("initializing template renderer: %w", err)
+	}
+
+	err = renderer.CopyStaticFiles(static...)
+	if err == nil && shelves != nil {
+		err = pages.RenderShelves(renderer, shelves, siteConfig.Features)
+	} else if err == nil {
+		err = pages.RenderSite(renderer, bookshelf, siteConfig.Features)
+	}
+	if err != nil {
+		if abortErr := renderer.Abort(); abortErr != nil {
+			return render.BuildSummary{}, fmt.Errorf("%w (discarding build: %v)", err, abortErr)
+		}
+		return render.BuildSummary{}, err
+	}
+
+	return renderer.Finish()
+}
+
+// loadShelves loads the bookshelves of all members.
+func loadShelves(shelfConfigs []config.Shelf) ([]dto.Shelf, error) {
+	shelves := make([]dto.Shelf, 0, len(shelfConfigs))
+
+	for _, shelfConfig := range shelfConfigs {
+		bookshelf, err := storage.Load(shelfConfig.Data)
+		if err != nil {
+			return nil, fmt.Errorf("loading shelf %s: %w", shelfConfig.Name, err)
+		}
+
+		owner := shelfConfig.Owner
+		if owner == "" {
+			owner = shelfConfig.Name
+		}
+
+		shelves = append(shelves, dto.Shelf{Name: shelfConfig.Name, Owner: owner, Bookshelf: bookshelf})
+	}
+
+	return shelves, nil
+}
+
+// buildOutputPath returns the directory the site is built into. Previews with
+// private books go next to the public output, e.g. dist-private, so they never
+// end up in the published site or its manifest.
+func buildOutputPath(paths config.Paths, includePrivate bool) string {
+	if includePrivate {
+		return filepath.Clean(paths.Output) + "-private"
+	}
+
+	return paths.Output
+}
+
+// visible returns the books, reviews and quotes of the bookshelf which are
+// rendered: the published ones only unless private ones are included.
+func visible(bookshelf *dto.Bookshelf, includePrivate bool) *dto.Bookshelf {
+	if includePrivate {
+		return bookshelf.IncludingPrivate()
+	}
+
+	return bookshelf.Public()
+}
@@ -0,0 +1,267 @@
+// Package admin serves forms to edit the bookshelf in server mode, e.g. to
+// update the reading progress from a phone, protected by a password.
+package admin
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"bookshelf/internal/dto"
+)
+
+// basePath is the path the admin area is served at.
+const basePath = "/admin"
+
+const csrfField = "csrf_token"
+
+// now is the clock for session expiry and the default date of progress
+// updates, replaceable in tests.
+var now = time.Now
+
+// checkPassword checks the passwords of logins, replaceable in tests.
+var checkPassword = CheckPassword
+
+//go:embed templates
+var templateFiles embed.FS
+
+type Config struct {
+	// PasswordHash is created by HashPassword
+	PasswordHash string
+	Store        dto.Store
+}
+
+type Admin struct {
+	config    Config
+	sessions  *sessions
+	throttle  *loginThrottle
+	templates map[string]*template.Template
+	mux       *http.ServeMux
+}
+
+// page is the data passed to all templates.
+type page struct {
+	Title     string
+	CSRFToken string
+	Notice    string
+	Errors    map[string]string
+	Data      any
+}
+
+func New(config Config) (*Admin, error) {
+	templates, err := parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Admin{
+		config:    config,
+		sessions:  newSessions(),
+		throttle:  newLoginThrottle(),
+		templates: templates,
+		mux:       http.NewServeMux(),
+	}
+
+	a.mux.HandleFunc("GET /admin/login", a.loginForm)
+	a.mux.HandleFunc("POST /admin/login", a.login)
+	a.mux.HandleFunc("POST /admin/logout", a.authenticated(a.logout))
+
+	a.mux.HandleFunc("GET /admin/{$}", a.authenticated(a.dashboard))
+	a.mux.HandleFunc("GET /admin/books/new", a.authenticated(a.newBook))
+	a.mux.HandleFunc("POST /admin/books", a.authenticated(a.createBook))
+	a.mux.HandleFunc("GET /admin/books/{id}", a.authenticated(a.editBook))
+	a.mux.HandleFunc("POST /admin/books/{id}", a.authenticated(a.updateBook))
+	a.mux.HandleFunc("POST /admin/books/{id}/progress", a.authenticated(a.updateProgress))
+	a.mux.HandleFunc("POST /admin/books/{id}/quotes", a.authenticated(a.addQuote))
+	a.mux.HandleFunc("POST /admin/books/{id}/delete", a.authenticated(a.deleteBook))
+
+	a.mux.HandleFunc("GET /admin/wishlist", a.authenticated(a.wishlist))
+	a.mux.HandleFunc("POST /admin/wishlist", a.authenticated(a.moveWishlistedBook))
+
+	a.mux.HandleFunc("GET /admin/collections", a.authenticated(a.collections))
+	a.mux.HandleFunc("GET /admin/collections/new", a.authenticated(a.newCollection))
+	a.mux.HandleFunc("POST /admin/collections", a.authenticated(a.createCollection))
+	a.mux.HandleFunc("GET /admin/collections/{name}", a.authenticated(a.editCollection))
+	a.mux.HandleFunc("POST /admin/collections/{name}", a.authenticated(a.updateCollection))
+	a.mux.HandleFunc("POST /admin/collections/{name}/delete", a.authenticated(a.deleteCollection))
+
+	return a, nil
+}
+
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	if r.Method == http.MethodPost && !sameOrigin(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	a.mux.ServeHTTP(w, r)
+}
+
+// authenticated redirects to the login form without a session and rejects
+// forms sent without the CSRF token of the session.
+func (a *Admin) authenticated(handler func(w http.ResponseWriter, r *http.Request, current session)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		current, ok := a.sessions.get(r)
+		if !ok {
+			http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
+			return
+		}
+
+		if r.Method == http.MethodPost && !current.validCSRFToken(r) {
+			http.Error(w, "Invalid or missing CSRF token, reload the page and try again", http.StatusForbidden)
+			return
+		}
+
+		handler(w, r, current)
+	}
+}
+
+func (a *Admin) loginForm(w http.ResponseWriter, r *http.Request) {
+	if _, ok := a.sessions.get(r); ok {
+		http.Redirect(w, r, basePath+"/", http.StatusSeeOther)
+		return
+	}
+
+	a.render(w, http.StatusOK, "login", page{Title: "Log in"})
+}
+
+func (a *Admin) login(w http.ResponseWriter, r *http.Request) {
+	// Throttled clients do not have to queue for a password check to learn
+	// that they have to wait
+	if wait := a.throttle.wait(r); wait > 0 {
+		a.throttled(w, wait)
+		return
+	}
+
+	password := r.PostFormValue("password")
+	ok, wait, err := a.throttle.check(r, func() bool {
+		return checkPassword(a.config.PasswordHash, password)
+	})
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	if wait > 0 {
+		a.throttled(w, wait)
+		return
+	}
+	if !ok {
+		a.render(w, http.StatusUnauthorized, "login", page{
+			Title:  "Log in",
+			Errors: map[string]string{"password": "is wrong"},
+		})
+		return
+	}
+
+	err = a.sessions.create(w, r)
+	if err != nil {
+		a.serverError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, basePath+"/", http.StatusSeeOther)
+}
+
+// throttled shows the login form to a client which has to wait before trying
+// again.
+func (a *Admin) throttled(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(wait.Round(time.Second) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, seconds)))
+	a.render(w, http.StatusTooManyRequests, "login", page{
+		Title:  "Log in",
+		Errors: map[string]string{"password": "was wrong too often, try again in " + wait.Round(time.Second).String()},
+	})
+}
+
+func (a *Admin) logout(w http.ResponseWriter, r *http.Request, current session) {
+	a.sessions.remove(w, r)
+
+	http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
+}
+
+// update applies the changes and redirects to the target on success. Invalid
+// input is shown in the form again with the errors of the fields.
+func (a *Admin) update(w http.ResponseWriter, r *http.Request, target string, update func(bookshelf *dto.Bookshelf) error, invalid func(errors map[string]string)) {
+	err := a.config.Store.Update(update)
+
+	var validationErr *dto.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		fieldErrors := make(map[string]string, len(validationErr.Errors))
+		for _, fieldError := range validationErr.Errors {
+			fieldErrors[fieldError.Field] = fieldError.Message
+		}
+		invalid(fieldErrors)
+	case errors.Is(err, dto.ErrBookNotFound), errors.Is(err, dto.ErrCollectionNotFound):
+		http.NotFound(w, r)
+	case err != nil:
+		a.serverError(w, err)
+	default:
+		http.Redirect(w, r, target+"?saved", http.StatusSeeOther)
+	}
+}
+
+func (a *Admin) render(w http.ResponseWriter, status int, name string, data page) {
+	if data.Errors == nil {
+		data.Errors = map[string]string{}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := a.templates[name].ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		log.Printf("Failed to render admin page %s: %v", name, err)
+	}
+}
+
+func (a *Admin) serverError(w http.ResponseWriter, err error) {
+	log.Printf("Failed to handle admin request: %v", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// notice returns the message shown after a change was saved.
+func notice(r *http.Request) string {
+	if r.URL.Query().Has("saved") {
+		return "Saved."
+	}
+
+	return ""
+}
+
+func parseTemplates() (map[string]*template.Template, error) {
+	funcs := template.FuncMap{
+		"path": func(segments ...string) string {
+			escaped := make([]string, len(segments))
+			for i, segment := range segments {
+				escaped[i] = url.PathEscape(segment)
+			}
+			return basePath + "/" + strings.Join(escaped, "/")
+		},
+		"join":       strings.Join,
+		"paragraphs": func(paragraphs []dto.Entry) string { return strings.Join(dto.Texts(paragraphs), "\n\n") },
+		"contains":   slices.Contains[[]string],
+	}
+
+	names := []string{"login", "dashboard", "book", "wishlist", "collections", "collection"}
+	templates := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		t, err := template.New(name).Funcs(funcs).ParseFS(templateFiles, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,418 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bookshelf/internal/dto"
+	"bookshelf/internal/storetest"
+)
+
+const testPassword = "secret"
+
+var csrfPattern = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+func TestMain(m *testing.M) {
+	now = func() time.Time { return time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC) }
+	os.Exit(m.Run())
+}
+
+// client sends requests with the session cookie and CSRF token of a login.
+type client struct {
+	t         *testing.T
+	admin     *Admin
+	cookie    *http.Cookie
+	csrfToken string
+}
+
+func newTestAdmin(t *testing.T) (*Admin, *storetest.Store) {
+	t.Helper()
+
+	store := storetest.New(&dto.Bookshelf{Books: []dto.Book{
+		{Id: "book-1", Title: "Book One", Authors: []string{"Author A"}, Pages: 300, Status: dto.StatusReading, Progress: dto.Progress{DateStarted: "2025-11-01", PagesRead: 100}},
+		{Id: "book-2", Title: "Book Two", Status: dto.StatusWishlisted, Rank: 1},
+		{Id: "book-3", Title: "Book Three", Status: dto.StatusWishlisted, Rank: 2},
+	}})
+
+	admin, err := New(Config{
+		// Few iterations keep the tests fast
+		PasswordHash: hashPassword(testPassword, []byte("salt"), 1),
+		Store:        store,
+	})
+	if err != nil {
+		t.Fatalf("could not create admin: %v", err)
+	}
+
+	return admin, store
+}
+
+func login(t *testing.T, admin *Admin) *client {
+	t.Helper()
+
+	c := &client{t: t, admin: admin}
+	resp := c.post("/admin/login", url.Values{"password": {testPassword}})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("could not log in, got status %d", resp.StatusCode)
+	}
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookieName {
+			c.cookie = cookie
+		}
+	}
+	if c.cookie == nil {
+		t.Fatal("expected session cookie after login")
+	}
+
+	match := csrfPattern.FindStringSubmatch(c.body(c.get("/admin/")))
+	if match == nil {
+		t.Fatal("expected CSRF token in the dashboard")
+	}
+	c.csrfToken = match[1]
+
+	return c
+}
+
+func (c *client) get(target string) *http.Response {
+	return c.do(httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+// post sends a form with the CSRF token of the session.
+func (c *client) post(target string, form url.Values) *http.Response {
+	if c.csrfToken != "" && !form.Has(csrfField) {
+		form.Set(csrfField, c.csrfToken)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req)
+}
+
+func (c *client) do(req *http.Request) *http.Response {
+	if c.cookie != nil {
+		req.AddCookie(c.cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	c.admin.ServeHTTP(rec, req)
+
+	return rec.Result()
+}
+
+func (c *client) body(resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatalf("could not read body: %v", err)
+	}
+
+	return string(body)
+}
+
+func TestLogin(t *testing.T) {
+	admin, _ := newTestAdmin(t)
+	anonymous := &client{t: t, admin: admin}
+
+	resp := anonymous.get("/admin/")
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/admin/login" {
+		t.Errorf("expected redirect to the login form, got status %d to %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	resp = anonymous.post("/admin/login", url.Values{"password": {"wrong"}})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected wrong password to be rejected, got status %d", resp.StatusCode)
+	}
+	if len(resp.Cookies()) > 0 {
+		t.Error("expected no session cookie for a wrong password")
+	}
+
+	c := login(t, admin)
+	if !c.cookie.HttpOnly || c.cookie.SameSite != http.SameSiteStrictMode || c.cookie.Path != "/admin" {
+		t.Errorf("expected HttpOnly, SameSite=Strict session cookie for /admin, got %+v", c.cookie)
+	}
+
+	resp = c.get("/admin/")
+	if body := c.body(resp); resp.StatusCode != http.StatusOK || !strings.Contains(body, "Book One") {
+		t.Errorf("expected dashboard listing the books, got status %d", resp.StatusCode)
+	}
+
+	c.post("/admin/logout", url.Values{})
+	if resp := c.get("/admin/"); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected session to end on logout, got status %d", resp.StatusCode)
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	admin, store := newTestAdmin(t)
+	c := login(t, admin)
+
+	resp := c.post("/admin/books/book-1/delete", url.Values{csrfField: {""}})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected form without CSRF token to be rejected, got status %d", resp.StatusCode)
+	}
+
+	resp = c.post("/admin/books/book-1/delete", url.Values{csrfField: {"forged"}})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected form with wrong CSRF token to be rejected, got status %d", resp.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/books/book-1/delete", strings.NewReader(csrfField+"="+url.QueryEscape(c.csrfToken)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://attacker.example")
+	if resp := c.do(req); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected cross-origin form to be rejected, got status %d", resp.StatusCode)
+	}
+
+	if len(store.Bookshelf().Books) != 3 {
+		t.Error("expected rejected forms not to change the bookshelf")
+	}
+}
+
+func TestBooks(t *testing.T) {
+	admin, store := newTestAdmin(t)
+	c := login(t, admin)
+
+	form := url.Values{
+		"title":      {"Book Four"},
+		"authors":    {"Author A, Author B"},
+		"status":     {dto.StatusToRead},
+		"pages":      {"250"},
+		"date_added": {"2025-11-20"},
+		"review":     {"First paragraph.\r\n\r\nSecond paragraph."},
+	}
+	resp := c.post("/admin/books", form)
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("expected book to be added, got status %d", resp.StatusCode)
+	}
+
+	book, ok := store.Bookshelf().Book("book-four")
+	if !ok {
+		t.Fatal("expected added book with id derived from the title")
+	}
+	if !reflect.DeepEqual(book.Authors, []string{"Author A", "Author B"}) || !reflect.DeepEqual(dto.Texts(book.Review), []string{"First paragraph.", "Second paragraph."}) {
+		t.Errorf("expected authors and review paragraphs to be split, got %+v", book)
+	}
+
+	// Invalid input is shown with the form again
+	form.Set("pages", "many")
+	form.Set("title", "")
+	resp = c.post("/admin/books/book-four", form)
+	if body := c.body(resp); resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(body, "must be a whole number") {
+		t.Errorf("expected invalid form to be shown with errors, got status %d", resp.StatusCode)
+	}
+
+	form.Set("pages", "260")
+	form.Set("title", "Book Four, Revised")
+	if resp := c.post("/admin/books/book-four", form); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected book to be updated, got status %d", resp.StatusCode)
+	}
+	if book, _ := store.Bookshelf().Book("book-four"); book.Title != "Book Four, Revised" || book.Pages != 260 {
+		t.Errorf("expected updated book, got %+v", book)
+	}
+
+	if resp := c.post("/admin/books/book-four/delete", url.Values{}); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected book to be deleted, got status %d", resp.StatusCode)
+	}
+	if resp := c.get("/admin/books/book-four"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected deleted book to be gone, got status %d", resp.StatusCode)
+	}
+}
+
+func TestProgressAndQuotes(t *testing.T) {
+	admin, store := newTestAdmin(t)
+	c := login(t, admin)
+
+	resp := c.post("/admin/books/book-1/progress", url.Values{"pages_read": {"150"}, "date": {"2025-11-20"}, "return": {"dashboard"}})
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/admin/?saved" {
+		t.Errorf("expected redirect to the dashboard, got status %d to %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	book, _ := store.Bookshelf().Book("book-1")
+	if book.Progress.PagesRead != 150 || !reflect.DeepEqual(book.Progress.Sessions, []dto.Session{{Date: "2025-11-20", Pages: 50}}) {
+		t.Errorf("expected progress with a reading session, got %+v", book.Progress)
+	}
+
+	resp = c.post("/admin/books/book-1/progress", url.Values{"pages_read": {"400"}, "date": {"2025-11-20"}})
+	if body := c.body(resp); resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(body, "must be between 0 and 300") {
+		t.Errorf("expected too many pages to be rejected, got status %d", resp.StatusCode)
+	}
+
+	if resp := c.post("/admin/books/book-1/quotes", url.Values{"quote": {"A quote."}}); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected quote to be added, got status %d", resp.StatusCode)
+	}
+	if book, _ := store.Bookshelf().Book("book-1"); !reflect.DeepEqual(dto.Texts(book.Quotes), []string{"A quote."}) {
+		t.Errorf("expected quote to be added, got %v", book.Quotes)
+	}
+}
+
+func TestWishlist(t *testing.T) {
+	admin, store := newTestAdmin(t)
+	c := login(t, admin)
+
+	if resp := c.post("/admin/wishlist", url.Values{"id": {"book-3"}, "direction": {"up"}}); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected book to be moved, got status %d", resp.StatusCode)
+	}
+
+	var ids []string
+	for _, book := range store.Bookshelf().WishlistedBooks() {
+		ids = append(ids, book.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"book-3", "book-2"}) {
+		t.Errorf("expected wishlist order [book-3 book-2], got %v", ids)
+	}
+
+	if resp := c.post("/admin/wishlist", url.Values{"id": {"book-1"}, "direction": {"up"}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected book not on the wishlist to be rejected, got status %d", resp.StatusCode)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	admin, store := newTestAdmin(t)
+	c := login(t, admin)
+
+	form := url.Values{"name": {"Favorites & More"}, "books": {"book-2", "book-1"}}
+	if resp := c.post("/admin/collections", form); resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("expected collection to be added, got status %d", resp.StatusCode)
+	}
+
+	resp := c.get("/admin/collections/Favorites%20&%20More")
+	if body := c.body(resp); resp.StatusCode != http.StatusOK || !strings.Contains(body, `value="Favorites &amp; More"`) {
+		t.Errorf("expected collection form, got status %d", resp.StatusCode)
+	}
+
+	form = url.Values{"name": {"Favorites"}, "books": {"missing"}}
+	if resp := c.post("/admin/collections/Favorites%20&%20More", form); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected unknown book to be rejected, got status %d", resp.StatusCode)
+	}
+
+	form = url.Values{"name": {"Favorites"}, "books": {"book-1"}}
+	if resp := c.post("/admin/collections/Favorites%20&%20More", form); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected collection to be updated, got status %d", resp.StatusCode)
+	}
+	if collection, ok := store.Bookshelf().Collection("Favorites"); !ok || !reflect.DeepEqual(collection.Books, []string{"book-1"}) {
+		t.Errorf("expected renamed collection, got %+v", store.Bookshelf().Collections)
+	}
+
+	if resp := c.post("/admin/collections/Favorites/delete", url.Values{}); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected collection to be deleted, got status %d", resp.StatusCode)
+	}
+	if len(store.Bookshelf().Collections) != 0 {
+		t.Errorf("expected no collections, got %+v", store.Bookshelf().Collections)
+	}
+}
+
+func TestPages(t *testing.T) {
+	admin, _ := newTestAdmin(t)
+
+	if resp := (&client{t: t, admin: admin}).get("/admin/login"); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected login form, got status %d", resp.StatusCode)
+	}
+
+	c := login(t, admin)
+	for _, target := range []string{"/admin/", "/admin/books/new", "/admin/books/book-1", "/admin/wishlist", "/admin/collections", "/admin/collections/new"} {
+		resp := c.get(target)
+		if body := c.body(resp); resp.StatusCode != http.StatusOK || !strings.Contains(body, c.csrfToken) {
+			t.Errorf("expected %s to be rendered with the CSRF token, got status %d", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestLogin_Throttle(t *testing.T) {
+	defer func(clock func() time.Time) { now = clock }(now)
+	start := now()
+
+	admin, _ := newTestAdmin(t)
+	anonymous := &client{t: t, admin: admin}
+
+	for range freeLoginAttempts {
+		if resp := anonymous.post("/admin/login", url.Values{"password": {"wrong"}}); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected wrong password to be rejected, got status %d", resp.StatusCode)
+		}
+	}
+
+	resp := anonymous.post("/admin/login", url.Values{"password": {testPassword}})
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "1" {
+		t.Errorf("expected attempts to be throttled after %d failures, got status %d", freeLoginAttempts, resp.StatusCode)
+	}
+
+	other := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader("password="+testPassword))
+	other.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	other.RemoteAddr = "198.51.100.7:4321"
+	if resp := anonymous.do(other); resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("expected other clients not to be throttled, got status %d", resp.StatusCode)
+	}
+
+	now = func() time.Time { return start.Add(time.Second) }
+	anonymous.post("/admin/login", url.Values{"password": {"wrong"}})
+	if resp := anonymous.post("/admin/login", url.Values{"password": {testPassword}}); resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("expected the wait to double with every failure, got %q", resp.Header.Get("Retry-After"))
+	}
+
+	now = func() time.Time { return start.Add(3 * time.Second) }
+	login(t, admin)
+	if wait := admin.throttle.wait(httptest.NewRequest(http.MethodPost, "/admin/login", nil)); wait != 0 {
+		t.Errorf("expected a login to reset the failures, got a wait of %s", wait)
+	}
+}
+
+func TestLogin_ConcurrentChecks(t *testing.T) {
+	admin, _ := newTestAdmin(t)
+	for range maxPasswordChecks {
+		admin.throttle.checks <- struct{}{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/admin/login", strings.NewReader("password="+testPassword))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if resp := (&client{t: t, admin: admin}).do(req); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the login to wait for a free password check, got status %d", resp.StatusCode)
+	}
+}
+
+func TestLogin_ConcurrentThrottle(t *testing.T) {
+	defer func(check func(hash, password string) bool) { checkPassword = check }(checkPassword)
+
+	var checked atomic.Int32
+	checkPassword = func(hash, password string) bool {
+		checked.Add(1)
+		// Keeps the checks running while the other requests arrive
+		time.Sleep(10 * time.Millisecond)
+		return CheckPassword(hash, password)
+	}
+
+	admin, _ := newTestAdmin(t)
+
+	var wg sync.WaitGroup
+	var throttled atomic.Int32
+	for range 4 * freeLoginAttempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := (&client{t: t, admin: admin}).post("/admin/login", url.Values{"password": {"wrong"}})
+			if resp.StatusCode == http.StatusTooManyRequests {
+				throttled.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if checked.Load() != freeLoginAttempts {
+		t.Errorf("expected only %d of the concurrent passwords to be checked, got %d", freeLoginAttempts, checked.Load())
+	}
+	if throttled.Load() != 3*freeLoginAttempts {
+		t.Errorf("expected the other %d attempts to be throttled, got %d", 3*freeLoginAttempts, throttled.Load())
+	}
+}
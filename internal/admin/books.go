@@ -0,0 +1,267 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+// statuses are the statuses in the order they are shown.
+var statuses = []string{dto.StatusReading, dto.StatusToRead, dto.StatusWishlisted, dto.StatusFinished}
+
+var visibilities = []string{dto.VisibilityPublic, dto.VisibilityPrivate, dto.VisibilityDraft}
+
+type shelf struct {
+	Status string
+	Books  []dto.Book
+}
+
+type dashboardData struct {
+	Today   string
+	Reading []dto.Book
+	Shelves []shelf
+}
+
+type bookData struct {
+	Book         dto.Book
+	New          bool
+	Statuses     []string
+	Visibilities []string
+	Today        string
+}
+
+func (a *Admin) dashboard(w http.ResponseWriter, r *http.Request, current session) {
+	// The admin area lists private and draft books as well
+	bookshelf := a.config.Store.Bookshelf().IncludingPrivate()
+	shelved := bookshelf.ShelvedBooks()
+	shelved[dto.StatusWishlisted] = bookshelf.WishlistedBooks()
+
+	data := dashboardData{Today: today(), Reading: shelved[dto.StatusReading]}
+	for _, status := range statuses {
+		data.Shelves = append(data.Shelves, shelf{Status: status, Books: shelved[status]})
+	}
+
+	a.render(w, http.StatusOK, "dashboard", page{Title: "Bookshelf", CSRFToken: current.csrfToken, Notice: notice(r), Data: data})
+}
+
+func (a *Admin) newBook(w http.ResponseWriter, r *http.Request, current session) {
+	book := dto.Book{Status: dto.StatusToRead, DateAdded: today()}
+
+	a.renderBook(w, http.StatusOK, current, "", book, true, nil)
+}
+
+func (a *Admin) createBook(w http.ResponseWriter, r *http.Request, current session) {
+	book, fieldErrors := parseBook(r, dto.Book{})
+
+	a.update(w, r, basePath+"/", func(bookshelf *dto.Bookshelf) error {
+		if fieldErrors != nil {
+			return fieldErrors
+		}
+
+		_, err := bookshelf.AddBook(book)
+		return err
+	}, func(errors map[string]string) {
+		a.renderBook(w, http.StatusUnprocessableEntity, current, "", book, true, errors)
+	})
+}
+
+func (a *Admin) editBook(w http.ResponseWriter, r *http.Request, current session) {
+	book, ok := a.config.Store.Bookshelf().Book(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	a.renderBook(w, http.StatusOK, current, notice(r), book, false, nil)
+}
+
+func (a *Admin) updateBook(w http.ResponseWriter, r *http.Request, current session) {
+	id := r.PathValue("id")
+	existing, ok := a.config.Store.Bookshelf().Book(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	book, fieldErrors := parseBook(r, existing)
+
+	a.update(w, r, basePath+"/books/"+id, func(bookshelf *dto.Bookshelf) error {
+		if fieldErrors != nil {
+			return fieldErrors
+		}
+
+		return bookshelf.UpdateBook(id, book)
+	}, func(errors map[string]string) {
+		a.renderBook(w, http.StatusUnprocessableEntity, current, "", book, false, errors)
+	})
+}
+
+func (a *Admin) updateProgress(w http.ResponseWriter, r *http.Request, current session) {
+	id := r.PathValue("id")
+	pagesRead, err := strconv.Atoi(strings.TrimSpace(r.PostFormValue("pages_read")))
+	if err != nil {
+		pagesRead = -1
+	}
+
+	// The dashboard sends progress updates as well, return to the page the
+	// form was sent from
+	target := basePath + "/books/" + id
+	if r.PostFormValue("return") == "dashboard" {
+		target = basePath + "/"
+	}
+
+	a.update(w, r, target, func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.UpdateProgress(id, pagesRead, strings.TrimSpace(r.PostFormValue("date")))
+	}, func(errors map[string]string) {
+		a.renderInvalidBook(w, r, current, errors)
+	})
+}
+
+func (a *Admin) addQuote(w http.ResponseWriter, r *http.Request, current session) {
+	id := r.PathValue("id")
+
+	a.update(w, r, basePath+"/books/"+id, func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.AddQuote(id, dto.Entry{Text: r.PostFormValue("quote"), Visibility: r.PostFormValue("visibility")})
+	}, func(errors map[string]string) {
+		a.renderInvalidBook(w, r, current, errors)
+	})
+}
+
+func (a *Admin) deleteBook(w http.ResponseWriter, r *http.Request, current session) {
+	id := r.PathValue("id")
+
+	a.update(w, r, basePath+"/", func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.DeleteBook(id)
+	}, func(errors map[string]string) {
+		a.renderInvalidBook(w, r, current, errors)
+	})
+}
+
+// renderInvalidBook shows the book page with the errors of one of its smaller
+// forms, e.g. the progress or quote form.
+func (a *Admin) renderInvalidBook(w http.ResponseWriter, r *http.Request, current session, errors map[string]string) {
+	book, ok := a.config.Store.Bookshelf().Book(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	a.renderBook(w, http.StatusUnprocessableEntity, current, "", book, false, errors)
+}
+
+func (a *Admin) renderBook(w http.ResponseWriter, status int, current session, notice string, book dto.Book, isNew bool, errors map[string]string) {
+	title := book.Title
+	if isNew {
+		title = "New book"
+	}
+
+	a.render(w, status, "book", page{
+		Title:     title,
+		CSRFToken: current.csrfToken,
+		Notice:    notice,
+		Errors:    errors,
+		Data:      bookData{Book: book, New: isNew, Statuses: statuses, Visibilities: visibilities, Today: today()},
+	})
+}
+
+// parseBook reads the book form into a copy of the existing book, so fields
+// not in the form like the reading sessions are kept.
+func parseBook(r *http.Request, existing dto.Book) (dto.Book, *dto.ValidationError) {
+	book := existing
+	fieldErrors := &dto.ValidationError{}
+
+	text := func(name string) string {
+		return strings.TrimSpace(r.PostFormValue(name))
+	}
+	number := func(name string, value *int) {
+		*value = 0
+		if s := text(name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				fieldErrors.Errors = append(fieldErrors.Errors, dto.FieldError{Field: name, Message: "must be a whole number"})
+			}
+			*value = n
+		}
+	}
+
+	if existing.Id == "" {
+		book.Id = text("id")
+	}
+	book.Title = text("title")
+	book.Subtitle = text("subtitle")
+	book.Authors = list(r.PostFormValue("authors"), ",")
+	book.Isbn = text("isbn")
+	book.Language = text("language")
+	book.Genre = text("genre")
+	book.Tags = list(r.PostFormValue("tags"), ",")
+	book.Cover = text("cover")
+	book.Link = text("link")
+	book.DateAdded = text("date_added")
+	book.Status = text("status")
+	book.Progress.DateStarted = text("date_started")
+	book.Progress.DateFinished = text("date_finished")
+	book.Visibility = text("visibility")
+	if book.Visibility == dto.VisibilityPublic {
+		book.Visibility = ""
+	}
+	book.Review = entries(list(r.PostFormValue("review"), "\n\n"), existing.Review)
+	book.Quotes = entries(list(r.PostFormValue("quotes"), "\n\n"), existing.Quotes)
+
+	number("year", &book.Year)
+	number("pages", &book.Pages)
+	number("rank", &book.Rank)
+	number("pages_read", &book.Progress.PagesRead)
+
+	book.Rating = 0
+	if s := text("rating"); s != "" {
+		rating, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			fieldErrors.Errors = append(fieldErrors.Errors, dto.FieldError{Field: "rating", Message: "must be a number"})
+		}
+		book.Rating = rating
+	}
+
+	if len(fieldErrors.Errors) == 0 {
+		return book, nil
+	}
+
+	return book, fieldErrors
+}
+
+// list splits a form value into its trimmed, non-empty parts, e.g. the
+// comma-separated authors or the paragraphs of a review.
+func list(value, separator string) []string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+
+	var parts []string
+	for _, part := range strings.Split(value, separator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+func today() string {
+	return now().Format("2006-01-02")
+}
+
+// entries returns the paragraphs of a review or the quotes as entries, the
+// form does not show their visibility, so paragraphs which were already there
+// keep theirs.
+func entries(texts []string, existing []dto.Entry) []dto.Entry {
+	visibility := make(map[string]string, len(existing))
+	for _, entry := range existing {
+		visibility[entry.Text] = entry.Visibility
+	}
+
+	var entries []dto.Entry
+	for _, text := range texts {
+		entries = append(entries, dto.Entry{Text: text, Visibility: visibility[text]})
+	}
+
+	return entries
+}
@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+type collectionData struct {
+	Collection dto.Collection
+	New        bool
+
+	// Books lists the books of the collection in their order followed by all
+	// other books, so the order is kept when the form is sent
+	Books []dto.Book
+}
+
+func (a *Admin) wishlist(w http.ResponseWriter, r *http.Request, current session) {
+	books := a.config.Store.Bookshelf().IncludingPrivate().WishlistedBooks()
+
+	a.render(w, http.StatusOK, "wishlist", page{Title: "Wishlist", CSRFToken: current.csrfToken, Notice: notice(r), Data: books})
+}
+
+// moveWishlistedBook moves a book one place up or down the wishlist.
+func (a *Admin) moveWishlistedBook(w http.ResponseWriter, r *http.Request, current session) {
+	id := r.PostFormValue("id")
+	direction := r.PostFormValue("direction")
+
+	a.update(w, r, basePath+"/wishlist", func(bookshelf *dto.Bookshelf) error {
+		var ids []string
+		for _, book := range bookshelf.IncludingPrivate().WishlistedBooks() {
+			ids = append(ids, book.Id)
+		}
+
+		i := slices.Index(ids, id)
+		if i < 0 {
+			return dto.ErrBookNotFound
+		}
+
+		j := i - 1
+		if direction == "down" {
+			j = i + 1
+		}
+		if j >= 0 && j < len(ids) {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+
+		return bookshelf.ReorderWishlist(ids)
+	}, func(errors map[string]string) {
+		http.Error(w, "Invalid wishlist order", http.StatusUnprocessableEntity)
+	})
+}
+
+func (a *Admin) collections(w http.ResponseWriter, r *http.Request, current session) {
+	collections := a.config.Store.Bookshelf().IncludingPrivate().BookCollections()
+
+	a.render(w, http.StatusOK, "collections", page{Title: "Collections", CSRFToken: current.csrfToken, Notice: notice(r), Data: collections})
+}
+
+func (a *Admin) newCollection(w http.ResponseWriter, r *http.Request, current session) {
+	a.renderCollection(w, http.StatusOK, current, "", dto.Collection{}, true, nil)
+}
+
+func (a *Admin) createCollection(w http.ResponseWriter, r *http.Request, current session) {
+	collection := parseCollection(r)
+
+	a.update(w, r, basePath+"/collections", func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.AddCollection(collection)
+	}, func(errors map[string]string) {
+		a.renderCollection(w, http.StatusUnprocessableEntity, current, "", collection, true, errors)
+	})
+}
+
+func (a *Admin) editCollection(w http.ResponseWriter, r *http.Request, current session) {
+	collection, ok := a.config.Store.Bookshelf().Collection(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	a.renderCollection(w, http.StatusOK, current, notice(r), collection, false, nil)
+}
+
+func (a *Admin) updateCollection(w http.ResponseWriter, r *http.Request, current session) {
+	name := r.PathValue("name")
+	collection := parseCollection(r)
+
+	a.update(w, r, basePath+"/collections", func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.UpdateCollection(name, collection)
+	}, func(errors map[string]string) {
+		a.renderCollection(w, http.StatusUnprocessableEntity, current, "", collection, false, errors)
+	})
+}
+
+func (a *Admin) deleteCollection(w http.ResponseWriter, r *http.Request, current session) {
+	name := r.PathValue("name")
+
+	a.update(w, r, basePath+"/collections", func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.DeleteCollection(name)
+	}, func(errors map[string]string) {
+		http.Error(w, "Invalid collection", http.StatusUnprocessableEntity)
+	})
+}
+
+func (a *Admin) renderCollection(w http.ResponseWriter, status int, current session, notice string, collection dto.Collection, isNew bool, errors map[string]string) {
+	bookshelf := a.config.Store.Bookshelf()
+
+	var books []dto.Book
+	for _, id := range collection.Books {
+		if book, ok := bookshelf.Book(id); ok {
+			books = append(books, book)
+		}
+	}
+
+	others := slices.DeleteFunc(slices.Clone(bookshelf.Books), func(book dto.Book) bool {
+		return slices.Contains(collection.Books, book.Id)
+	})
+	slices.SortFunc(others, func(a, b dto.Book) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+
+	title := collection.Name
+	if isNew {
+		title = "New collection"
+	}
+
+	a.render(w, status, "collection", page{
+		Title:     title,
+		CSRFToken: current.csrfToken,
+		Notice:    notice,
+		Errors:    errors,
+		Data:      collectionData{Collection: collection, New: isNew, Books: append(books, others...)},
+	})
+}
+
+func parseCollection(r *http.Request) dto.Collection {
+	r.ParseForm()
+
+	return dto.Collection{
+		Name:        strings.TrimSpace(r.PostFormValue("name")),
+		Description: strings.TrimSpace(r.PostFormValue("description")),
+		Books:       r.PostForm["books"],
+	}
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2(t *testing.T) {
+	// Test vector of RFC 7914, section 11
+	key := pbkdf2([]byte("passwd"), []byte("salt"), 1)
+
+	expected := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("expected key %s, got %x", expected, key)
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("could not hash password: %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$600000$") {
+		t.Errorf("expected hash with scheme and iterations, got %s", hash)
+	}
+	if !CheckPassword(hash, "secret") {
+		t.Error("expected password to match its hash")
+	}
+	if CheckPassword(hash, "Secret") {
+		t.Error("expected other password not to match")
+	}
+
+	for _, hash := range []string{"", "secret", "md5$1$c2FsdA$a2V5", "pbkdf2-sha256$0$c2FsdA$a2V5", "pbkdf2-sha256$1$!$a2V5"} {
+		if CheckPassword(hash, "secret") {
+			t.Errorf("expected invalid hash %q not to match", hash)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookieName = "bookshelf_admin"
+
+	// sessionDuration is long, so family members updating their progress
+	// from a phone do not have to log in every time
+	sessionDuration = 30 * 24 * time.Hour
+)
+
+// sessions keeps the logged in sessions in memory, they end when the server
+// is restarted.
+type sessions struct {
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+type session struct {
+	// csrfToken has to be sent with every form, so other sites can not submit
+	// forms on behalf of a logged in user
+	csrfToken string
+	expires   time.Time
+}
+
+func newSessions() *sessions {
+	return &sessions{sessions: make(map[string]session)}
+}
+
+// create starts a session and sets its cookie.
+func (s *sessions) create(w http.ResponseWriter, r *http.Request) error {
+	id, err := randomToken()
+	if err != nil {
+		return err
+	}
+	csrfToken, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	expires := now().Add(sessionDuration)
+
+	s.mu.Lock()
+	s.removeExpired()
+	s.sessions[id] = session{csrfToken: csrfToken, expires: expires}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    id,
+		Path:     basePath,
+		Expires:  expires,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return nil
+}
+
+// get returns the session of the request.
+func (s *sessions) get(r *http.Request) (session, bool) {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return session{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.sessions[cookie.Value]
+	if !ok || now().After(current.expires) {
+		delete(s.sessions, cookie.Value)
+		return session{}, false
+	}
+
+	return current, true
+}
+
+// remove ends the session of the request and removes its cookie.
+func (s *sessions) remove(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, cookie.Value)
+		s.mu.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Path:     basePath,
+		MaxAge:   -1,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func (s *sessions) removeExpired() {
+	for id, current := range s.sessions {
+		if now().After(current.expires) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// validCSRFToken reports whether the form was sent with the token of the
+// session.
+func (current session) validCSRFToken(r *http.Request) bool {
+	token := r.PostFormValue(csrfField)
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(current.csrfToken)) == 1
+}
+
+// sameOrigin reports whether a request was sent from a page of this site,
+// browsers send the Origin header with every form submission.
+func sameOrigin(r *http.Request) bool {
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == r.Host
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
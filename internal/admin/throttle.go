@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// freeLoginAttempts is the number of wrong passwords a client may send
+	// before it has to wait between attempts
+	freeLoginAttempts = 3
+
+	// maxLoginBackoff caps the wait, the backoff doubles with every failure
+	// after the free attempts
+	maxLoginBackoff = 15 * time.Minute
+
+	// maxPasswordChecks is the number of passwords checked at once, each
+	// check takes a CPU core for a moment
+	maxPasswordChecks = 2
+)
+
+// loginThrottle slows down guessing the password: clients have to wait longer
+// after every wrong password and only a few passwords are checked at once.
+type loginThrottle struct {
+	mu       sync.Mutex
+	failures map[string]loginFailures
+	checks   chan struct{}
+}
+
+type loginFailures struct {
+	count int
+	last  time.Time
+}
+
+func newLoginThrottle() *loginThrottle {
+	return &loginThrottle{
+		failures: make(map[string]loginFailures),
+		checks:   make(chan struct{}, maxPasswordChecks),
+	}
+}
+
+// wait returns how long the client of the request has to wait before its
+// next attempt.
+func (t *loginThrottle) wait(r *http.Request) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.clientWait(clientAddress(r))
+}
+
+func (t *loginThrottle) clientWait(client string) time.Duration {
+	failures, ok := t.failures[client]
+	if !ok {
+		return 0
+	}
+
+	return max(0, failures.last.Add(loginBackoff(failures.count)).Sub(now()))
+}
+
+// check runs the password check once fewer than maxPasswordChecks are running
+// and records the result for the client of the request. It gives up when the
+// request is cancelled while waiting.
+//
+// An attempt counts as a failure until the password turns out to be right,
+// so a client sending many passwords at once can not try more of them than
+// one after the other: once it has to wait, the password is not checked and
+// the wait is returned instead.
+func (t *loginThrottle) check(r *http.Request, check func() bool) (bool, time.Duration, error) {
+	select {
+	case t.checks <- struct{}{}:
+	case <-r.Context().Done():
+		return false, 0, r.Context().Err()
+	}
+	defer func() { <-t.checks }()
+
+	client := clientAddress(r)
+
+	t.mu.Lock()
+	if wait := t.clientWait(client); wait > 0 {
+		t.mu.Unlock()
+		return false, wait, nil
+	}
+	t.removeExpired()
+	failures := t.failures[client]
+	t.failures[client] = loginFailures{count: failures.count + 1, last: now()}
+	t.mu.Unlock()
+
+	if !check() {
+		return false, 0, nil
+	}
+
+	t.mu.Lock()
+	delete(t.failures, client)
+	t.mu.Unlock()
+
+	return true, 0, nil
+}
+
+// removeExpired forgets clients which may try again without waiting.
+func (t *loginThrottle) removeExpired() {
+	for client, failures := range t.failures {
+		if now().Sub(failures.last) > maxLoginBackoff {
+			delete(t.failures, client)
+		}
+	}
+}
+
+// loginBackoff returns the wait after the given number of wrong passwords.
+func loginBackoff(failures int) time.Duration {
+	if failures < freeLoginAttempts {
+		return 0
+	}
+
+	backoff := time.Second << min(failures-freeLoginAttempts, 20)
+
+	return min(backoff, maxLoginBackoff)
+}
+
+// clientAddress returns the IP address of the client without its port.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,28 @@
+// Package api defines the documents of the static JSON API. The field names are
+// part of the public interface and documented in docs/api.md, changing them
+// requires a new Version.
+package api
+
+import (
+	"encoding/json"
+)
+
+const Version = 1
+
+// URLFunc returns the absolute URL of a path relative to the site.
+type URLFunc func(path string) string
+
+type Document struct {
+	Version int `json:"version"`
+	Data    any `json:"data"`
+}
+
+// Marshal wraps the data into a versioned document.
+func Marshal(data any) ([]byte, error) {
+	content, err := json.MarshalIndent(Document{Version: Version, Data: data}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(content, '\n'), nil
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"bookshelf/internal/dto"
+)
+
+const schemaDocumentation = "../../docs/api.md"
+
+// documentedTypes maps the sections of the documentation to their types.
+var documentedTypes = map[string]reflect.Type{
+	"Book":          reflect.TypeOf(Book{}),
+	"Progress":      reflect.TypeOf(Progress{}),
+	"Collection":    reflect.TypeOf(Collection{}),
+	"BookReference": reflect.TypeOf(BookReference{}),
+	"Quote":         reflect.TypeOf(Quote{}),
+	"Stats":         reflect.TypeOf(Stats{}),
+	"Count":         reflect.TypeOf(Count{}),
+}
+
+var documentedField = regexp.MustCompile("^\\| `([a-z_]+)`")
+
+func documentedFields(t *testing.T) map[string][]string {
+	file, err := os.Open(schemaDocumentation)
+	if err != nil {
+		t.Fatalf("could not open schema documentation: %v", err)
+	}
+	defer file.Close()
+
+	fields := make(map[string][]string)
+	section := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "## ") {
+			section = strings.TrimPrefix(line, "## ")
+			continue
+		}
+
+		if match := documentedField.FindStringSubmatch(line); match != nil && section != "" {
+			fields[section] = append(fields[section], match[1])
+		}
+	}
+
+	return fields
+}
+
+func jsonFields(typ reflect.Type) []string {
+	fields := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+
+	return fields
+}
+
+func TestSchemaDocumentation(t *testing.T) {
+	documented := documentedFields(t)
+
+	for section, typ := range documentedTypes {
+		t.Run(section, func(t *testing.T) {
+			expected := jsonFields(typ)
+			actual := documented[section]
+
+			sort.Strings(expected)
+			sort.Strings(actual)
+
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("documented fields %v do not match the fields %v", actual, expected)
+			}
+		})
+	}
+
+	for section := range documented {
+		if _, ok := documentedTypes[section]; !ok {
+			t.Errorf("documented section %s has no type", section)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	book := dto.Book{
+		Id:       "book-1",
+		Title:    "Book One",
+		Status:   dto.StatusReading,
+		Forecast: &dto.Forecast{ExpectedFinish: "2025-11-26"},
+	}
+
+	content, err := Marshal(NewBook(book, func(path string) string {
+		return "https://example.com/" + path
+	}))
+	if err != nil {
+		t.Fatalf("could not marshal book: %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("could not unmarshal document: %v", err)
+	}
+
+	if document["version"] != float64(Version) {
+		t.Errorf("expected version %d, got %v", Version, document["version"])
+	}
+
+	data := document["data"].(map[string]any)
+	if data["url"] != "https://example.com/book-1.html" {
+		t.Errorf("expected an absolute url, got %v", data["url"])
+	}
+
+	if authors, ok := data["authors"].([]any); !ok || len(authors) != 0 {
+		t.Errorf("expected empty lists to be encoded as [], got %v", data["authors"])
+	}
+
+	progress := data["progress"].(map[string]any)
+	if progress["expected_finish"] != "2025-11-26" {
+		t.Errorf("expected the forecast as expected finish, got %v", progress["expected_finish"])
+	}
+}
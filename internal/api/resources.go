@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bookshelf/internal/dto"
+)
+
+type Book struct {
+	Id        string   `json:"id"`
+	Url       string   `json:"url"`
+	Isbn      string   `json:"isbn"`
+	Title     string   `json:"title"`
+	Subtitle  string   `json:"subtitle"`
+	Authors   []string `json:"authors"`
+	Year      int      `json:"year"`
+	Language  string   `json:"language"`
+	Pages     int      `json:"pages"`
+	Genre     string   `json:"genre"`
+	Tags      []string `json:"tags"`
+	Cover     string   `json:"cover"`
+	Link      string   `json:"link"`
+	DateAdded string   `json:"date_added"`
+	Status    string   `json:"status"`
+	Rank      int      `json:"rank"`
+	Progress  Progress `json:"progress"`
+	Rating    float64  `json:"rating"`
+	Review    []string `json:"review"`
+	Quotes    []string `json:"quotes"`
+}
+
+type Progress struct {
+	DateStarted    string `json:"date_started"`
+	DateFinished   string `json:"date_finished"`
+	PagesRead      int    `json:"pages_read"`
+	ExpectedFinish string `json:"expected_finish"`
+}
+
+type BookReference struct {
+	Id    string `json:"id"`
+	Url   string `json:"url"`
+	Title string `json:"title"`
+	Rank  int    `json:"rank"`
+}
+
+type Collection struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Books       []BookReference `json:"books"`
+}
+
+type Quote struct {
+	Quote     string   `json:"quote"`
+	Authors   []string `json:"authors"`
+	BookId    string   `json:"book_id"`
+	BookTitle string   `json:"book_title"`
+	Url       string   `json:"url"`
+}
+
+type Stats struct {
+	TotalBooks            int     `json:"total_books"`
+	BooksFinished         int     `json:"books_finished"`
+	BooksFinishedThisYear int     `json:"books_finished_this_year"`
+	PagesRead             int     `json:"pages_read"`
+	PagesReadThisYear     int     `json:"pages_read_this_year"`
+	AverageRating         float64 `json:"average_rating"`
+	AveragePages          float64 `json:"average_pages"`
+	TopGenres             []Count `json:"top_genres"`
+	BooksByStatus         []Count `json:"books_by_status"`
+	BooksByLanguage       []Count `json:"books_by_language"`
+	BooksPerMonth         []Count `json:"books_per_month"`
+	RatingDistribution    []Count `json:"rating_distribution"`
+}
+
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+func NewBook(book dto.Book, url URLFunc) Book {
+	resource := Book{
+		Id:        book.Id,
+		Url:       url(book.Id + ".html"),
+		Isbn:      book.Isbn,
+		Title:     book.Title,
+		Subtitle:  book.Subtitle,
+		Authors:   nonNil(book.Authors),
+		Year:      book.Year,
+		Language:  book.Language,
+		Pages:     book.Pages,
+		Genre:     book.Genre,
+		Tags:      nonNil(book.Tags),
+		Cover:     book.Cover,
+		Link:      book.Link,
+		DateAdded: book.DateAdded,
+		Status:    book.Status,
+		Rank:      book.Rank,
+		Progress: Progress{
+			DateStarted:  book.Progress.DateStarted,
+			DateFinished: book.Progress.DateFinished,
+			PagesRead:    book.Progress.PagesRead,
+		},
+		Rating: book.Rating,
+		Review: nonNil(dto.Texts(book.Review)),
+		Quotes: nonNil(dto.Texts(book.Quotes)),
+	}
+
+	if book.Forecast != nil {
+		resource.Progress.ExpectedFinish = book.Forecast.ExpectedFinish
+	}
+
+	return resource
+}
+
+func NewCollection(collection dto.ResolvedCollection, url URLFunc) Collection {
+	resource := Collection{
+		Name:        collection.Name,
+		Description: collection.Description,
+		Books:       make([]BookReference, 0, len(collection.Books)),
+	}
+
+	for _, book := range collection.Books {
+		resource.Books = append(resource.Books, BookReference{
+			Id:    book.Id,
+			Url:   url(book.Id + ".html"),
+			Title: book.Title,
+			Rank:  book.Rank,
+		})
+	}
+
+	return resource
+}
+
+func NewQuote(quote dto.Quote, url URLFunc) Quote {
+	return Quote{
+		Quote:     quote.Quote,
+		Authors:   nonNil(quote.Authors),
+		BookId:    quote.Id,
+		BookTitle: quote.BookTitle,
+		Url:       url(quote.Id + ".html"),
+	}
+}
+
+func NewStats(stats dto.Stats) Stats {
+	return Stats{
+		TotalBooks:            stats.TotalBooks,
+		BooksFinished:         stats.BooksFinished,
+		BooksFinishedThisYear: stats.BooksFinishedThisYear,
+		PagesRead:             stats.PagesRead,
+		PagesReadThisYear:     stats.PagesReadThisYear,
+		AverageRating:         stats.AverageRating,
+		AveragePages:          stats.AveragePages,
+		TopGenres:             newCounts(stats.TopGenres),
+		BooksByStatus:         newCounts(stats.BooksByStatus),
+		BooksByLanguage:       newCounts(stats.BooksByLanguage),
+		BooksPerMonth:         newCounts(stats.BooksPerMonth),
+		RatingDistribution:    newCounts(stats.RatingDistribution),
+	}
+}
+
+func newCounts(statCounts []dto.StatCount) []Count {
+	counts := make([]Count, 0, len(statCounts))
+	for _, c := range statCounts {
+		counts = append(counts, Count{Value: c.Value, Count: c.Count})
+	}
+
+	return counts
+}
+
+// nonNil makes sure empty lists are encoded as [] instead of null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
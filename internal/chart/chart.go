@@ -0,0 +1,133 @@
+// Package chart renders statistics as accessible inline SVG, so charts can be
+// embedded into the statically generated pages without any JavaScript.
+package chart
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+const (
+	chartWidth   = 360
+	chartHeight  = 140
+	labelHeight  = 16
+	seriesColors = 6
+)
+
+// Bar renders one bar per value, e.g. the books finished per month.
+func Bar(title string, counts []dto.StatCount) template.HTML {
+	return bars("bar-chart", title, counts, 0.3)
+}
+
+// Histogram renders adjacent bars for consecutive buckets, e.g. ratings.
+func Histogram(title string, counts []dto.StatCount) template.HTML {
+	return bars("histogram", title, counts, 0.05)
+}
+
+// Donut renders the share of each value as a segment of a ring with a legend.
+func Donut(title string, counts []dto.StatCount) template.HTML {
+	const radius, stroke, center = 40.0, 16.0, 50.0
+	circumference := 2 * math.Pi * radius
+	height := max(100, len(counts)*16+8)
+	total := totalCount(counts)
+
+	var sb strings.Builder
+	openSVG(&sb, "donut-chart", title, counts, chartWidth, height)
+
+	// Each segment starts where the previous one ended, the dash pattern repeats
+	// every circumference so the offset is counted backwards from it.
+	offset := 0.0
+	for i, c := range counts {
+		if total == 0 {
+			break
+		}
+
+		length := circumference * float64(c.Count) / float64(total)
+		fmt.Fprintf(&sb, `<circle class="series-%d" cx="%g" cy="%g" r="%g" fill="none" stroke-width="%g" stroke-dasharray="%.2f %.2f" stroke-dashoffset="%.2f" transform="rotate(-90 %g %g)"><title>%s</title></circle>`,
+			i%seriesColors, center, center, radius, stroke, length, circumference-length, circumference-offset, center, center, escape(countLabel(c)))
+		offset += length
+	}
+
+	for i, c := range counts {
+		y := i*16 + 8
+		fmt.Fprintf(&sb, `<rect class="series-%d" x="110" y="%d" width="10" height="10" rx="2"></rect>`, i%seriesColors, y)
+		fmt.Fprintf(&sb, `<text x="126" y="%d">%s</text>`, y+9, escape(countLabel(c)))
+	}
+
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
+
+func bars(class, title string, counts []dto.StatCount, gapRatio float64) template.HTML {
+	var sb strings.Builder
+	openSVG(&sb, class, title, counts, chartWidth, chartHeight)
+
+	plotHeight := float64(chartHeight - labelHeight)
+	maxCount := maxCount(counts)
+
+	if len(counts) > 0 {
+		slot := float64(chartWidth) / float64(len(counts))
+		gap := slot * gapRatio
+
+		for i, c := range counts {
+			barHeight := 0.0
+			if maxCount > 0 {
+				barHeight = plotHeight * float64(c.Count) / float64(maxCount)
+			}
+
+			x := float64(i) * slot
+			fmt.Fprintf(&sb, `<rect class="bar" x="%.2f" y="%.2f" width="%.2f" height="%.2f"><title>%s</title></rect>`,
+				x+gap/2, plotHeight-barHeight, slot-gap, barHeight, escape(countLabel(c)))
+			fmt.Fprintf(&sb, `<text x="%.2f" y="%d" text-anchor="middle">%s</text>`,
+				x+slot/2, chartHeight-4, escape(c.Value))
+		}
+	}
+
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
+
+// openSVG writes the svg element with a title and a textual description of all
+// values, so screen readers get the same information as the chart shows.
+func openSVG(sb *strings.Builder, class, title string, counts []dto.StatCount, width, height int) {
+	description := make([]string, 0, len(counts))
+	for _, c := range counts {
+		description = append(description, countLabel(c))
+	}
+
+	fmt.Fprintf(sb, `<svg class="chart %s" role="img" viewBox="0 0 %d %d">`, class, width, height)
+	fmt.Fprintf(sb, `<title>%s</title>`, escape(title))
+	fmt.Fprintf(sb, `<desc>%s</desc>`, escape(strings.Join(description, ", ")))
+}
+
+func countLabel(c dto.StatCount) string {
+	return fmt.Sprintf("%s: %d", c.Value, c.Count)
+}
+
+func totalCount(counts []dto.StatCount) int {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+
+	return total
+}
+
+func maxCount(counts []dto.StatCount) int {
+	maxCount := 0
+	for _, c := range counts {
+		maxCount = max(maxCount, c.Count)
+	}
+
+	return maxCount
+}
+
+func escape(s string) string {
+	return template.HTMLEscapeString(s)
+}
@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+
+	"bookshelf/internal/dto"
+)
+
+func testCounts() []dto.StatCount {
+	return []dto.StatCount{
+		{Value: "en", Count: 3},
+		{Value: "de", Count: 1},
+		{Value: "<fr>", Count: 0},
+	}
+}
+
+func TestBar(t *testing.T) {
+	svg := string(Bar("Books per month", testCounts()))
+
+	if !strings.HasPrefix(svg, `<svg class="chart bar-chart" role="img"`) {
+		t.Errorf("expected an accessible svg element, got %s", svg)
+	}
+
+	if !strings.Contains(svg, "<title>Books per month</title>") {
+		t.Error("expected the chart title to be rendered")
+	}
+
+	if !strings.Contains(svg, "<desc>en: 3, de: 1, &lt;fr&gt;: 0</desc>") {
+		t.Errorf("expected an escaped description of all values, got %s", svg)
+	}
+
+	if count := strings.Count(svg, `<rect class="bar"`); count != 3 {
+		t.Errorf("expected 3 bars, got %d", count)
+	}
+
+	if strings.Contains(svg, "<fr>") {
+		t.Error("expected values to be escaped")
+	}
+}
+
+func TestBar_Empty(t *testing.T) {
+	svg := string(Bar("Empty", nil))
+
+	if !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("expected a complete svg element, got %s", svg)
+	}
+
+	if strings.Contains(svg, "<rect") {
+		t.Error("expected no bars for empty values")
+	}
+}
+
+func TestHistogram_ZeroCounts(t *testing.T) {
+	svg := string(Histogram("Ratings", []dto.StatCount{{Value: "4.0", Count: 0}}))
+
+	if !strings.Contains(svg, `height="0.00"`) {
+		t.Errorf("expected an empty bar for zero counts, got %s", svg)
+	}
+}
+
+func TestDonut(t *testing.T) {
+	svg := string(Donut("Books by language", testCounts()))
+
+	if count := strings.Count(svg, "<circle"); count != 3 {
+		t.Errorf("expected 3 segments, got %d", count)
+	}
+
+	if count := strings.Count(svg, "<text"); count != 3 {
+		t.Errorf("expected 3 legend entries, got %d", count)
+	}
+
+	// The first segment covers 3/4 of the circumference (2*pi*40)
+	if !strings.Contains(svg, `stroke-dasharray="188.50 62.83"`) {
+		t.Errorf("expected first segment to cover 3/4 of the ring, got %s", svg)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	activity := dto.Activity{
+		ActiveDays: 1,
+		Weeks: [][]dto.ActivityDay{
+			{{Date: "2025-11-16"}, {Date: "2025-11-17", Count: 1, Level: 1}},
+		},
+	}
+
+	svg := string(Heatmap(activity))
+
+	if count := strings.Count(svg, "<rect"); count != 2 {
+		t.Errorf("expected 2 days, got %d", count)
+	}
+
+	if !strings.Contains(svg, `<rect class="level-1" x="0" y="12"`) {
+		t.Errorf("expected monday to be rendered in the second row, got %s", svg)
+	}
+
+	if !strings.Contains(svg, "<title>2025-11-17: 1 book read</title>") {
+		t.Errorf("expected a label per day, got %s", svg)
+	}
+}
@@ -0,0 +1,51 @@
+package chart
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+const (
+	heatmapCellSize = 10
+	heatmapCellGap  = 2
+)
+
+// Heatmap renders the reading activity as a calendar heatmap with one column
+// per week and one row per weekday.
+func Heatmap(activity dto.Activity) template.HTML {
+	step := heatmapCellSize + heatmapCellGap
+	width := len(activity.Weeks)*step - heatmapCellGap
+	height := 7*step - heatmapCellGap
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg class="chart heatmap" role="img" viewBox="0 0 %d %d">`, max(width, 0), height)
+	sb.WriteString(`<title>Reading activity</title>`)
+	fmt.Fprintf(&sb, `<desc>%s</desc>`, escape(fmt.Sprintf("%d active days, current streak %d days, longest streak %d days",
+		activity.ActiveDays, activity.CurrentStreak, activity.LongestStreak)))
+
+	for x, week := range activity.Weeks {
+		// Weeks start on sunday, so the position within the week is the weekday
+		for y, day := range week {
+			fmt.Fprintf(&sb, `<rect class="level-%d" x="%d" y="%d" width="%d" height="%d" rx="2"><title>%s</title></rect>`,
+				day.Level, x*step, y*step, heatmapCellSize, heatmapCellSize, escape(dayLabel(day)))
+		}
+	}
+
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
+
+func dayLabel(day dto.ActivityDay) string {
+	switch day.Count {
+	case 0:
+		return fmt.Sprintf("%s: no books read", day.Date)
+	case 1:
+		return fmt.Sprintf("%s: 1 book read", day.Date)
+	default:
+		return fmt.Sprintf("%s: %d books read", day.Date, day.Count)
+	}
+}
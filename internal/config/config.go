@@ -0,0 +1,239 @@
+// Package config loads the site configuration, so the site can be customized
+// without editing code or templates.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+type Config struct {
+	Site     Site     `json:"site"`
+	Paths    Paths    `json:"paths"`
+	Features Features `json:"features"`
+	Admin    Admin    `json:"admin"`
+	REST     REST     `json:"rest"`
+
+	BookshelfPage BookshelfPage `json:"bookshelf_page"`
+
+	// Shelves replace the data file with a shelf per member of a household
+	Shelves []Shelf `json:"shelves"`
+}
+
+type Site struct {
+	Title       string     `json:"title"`
+	Subtitle    string     `json:"subtitle"`
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	Initials    string     `json:"initials"`
+	Language    string     `json:"language"`
+	BaseURL     string     `json:"base_url"`
+	PathPrefix  string     `json:"path_prefix"`
+	Image       string     `json:"image"`
+	Nav         []NavEntry `json:"nav"`
+
+	// Footer is trusted HTML from the configuration and rendered as is
+	Footer template.HTML `json:"footer"`
+}
+
+type NavEntry struct {
+	Label     string `json:"label"`
+	Href      string `json:"href"`
+	Highlight bool   `json:"highlight"`
+}
+
+// featurePages are the pages which are only rendered with their feature.
+var featurePages = map[string]func(Features) bool{
+	"stats.html": func(f Features) bool { return f.Stats },
+}
+
+// EnabledNav returns the navigation entries without the links to pages of
+// disabled features, e.g. stats.html without the stats feature.
+func (s Site) EnabledNav(features Features) []NavEntry {
+	return slices.DeleteFunc(slices.Clone(s.Nav), func(entry NavEntry) bool {
+		u, err := url.Parse(entry.Href)
+		if err != nil || u.Scheme != "" || u.Host != "" {
+			return false
+		}
+
+		enabled, ok := featurePages[path.Base(u.Path)]
+		return ok && !enabled(features)
+	})
+}
+
+type Paths struct {
+	// Data is a JSON file or an SQLite database ending in .db, .sqlite or
+	// .sqlite3
+	Data      string `json:"data"`
+	Templates string `json:"templates"`
+	Static    string `json:"static"`
+	Output    string `json:"output"`
+
+	// Theme and Overrides are optional template directories layered on top of
+	// the default templates, overrides take precedence over the theme
+	Theme     string `json:"theme"`
+	Overrides string `json:"overrides"`
+}
+
+type Features struct {
+	Stats   bool `json:"stats"`
+	Feeds   bool `json:"feeds"`
+	API     bool `json:"api"`
+	Sitemap bool `json:"sitemap"`
+	Search  bool `json:"search"`
+}
+
+// BookshelfPage configures the sections of the bookshelf page.
+type BookshelfPage struct {
+	// Sections are the statuses of the books listed, in their order
+	Sections []string `json:"sections"`
+	// Sort is the order books are listed in until a visitor changes it, one
+	// of title, author, rating, date_finished, pages or year
+	Sort string `json:"sort"`
+}
+
+// Shelf is the bookshelf of one member, rendered to the directory of its name.
+// The owner defaults to the name.
+type Shelf struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+	Data  string `json:"data"`
+}
+
+// shelfNamePattern allows names usable as directory in URLs, e.g. "alice".
+var shelfNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// reservedShelfNames are directories of the static files and the JSON API.
+var reservedShelfNames = []string{"api", "css", "icons", "js"}
+
+// Admin configures the admin area of the server mode, it is only served if a
+// password hash is set, e.g. created with "go run . hash-password".
+type Admin struct {
+	PasswordHash string `json:"password_hash"`
+}
+
+// REST configures the REST API of the server mode, it is only served if
+// tokens are set, e.g. created with "go run . generate-token".
+type REST struct {
+	TokenHashes []string `json:"token_hashes"`
+}
+
+// Default returns the configuration used for all values missing in the
+// configuration file.
+func Default() Config {
+	return Config{
+		Site: Site{
+			Title:    "My Digital Bookshelf",
+			Language: "en",
+			Nav: []NavEntry{
+				{Label: "Home", Href: "index.html"},
+				{Label: "Bookshelf", Href: "bookshelf.html"},
+				{Label: "Collections", Href: "collections.html"},
+				{Label: "Quotes", Href: "quotes.html"},
+				{Label: "Stats", Href: "stats.html"},
+				{Label: "Wishlist", Href: "wishlist.html", Highlight: true},
+			},
+		},
+		Paths: Paths{
+			Data:      "data/data.json",
+			Templates: "templates",
+			Static:    "static",
+			Output:    "dist",
+		},
+		Features: Features{
+			Stats:   true,
+			Feeds:   true,
+			API:     true,
+			Sitemap: true,
+			Search:  true,
+		},
+		BookshelfPage: BookshelfPage{
+			Sections: []string{dto.StatusReading, dto.StatusToRead, dto.StatusFinished},
+			Sort:     dto.SortByTitle,
+		},
+	}
+}
+
+func Load(path string) (Config, error) {
+	config := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	if err := config.validateShelves(); err != nil {
+		return config, err
+	}
+
+	if err := config.validateBookshelfPage(); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+func (c Config) validateShelves() error {
+	seen := make(map[string]bool)
+
+	for _, shelf := range c.Shelves {
+		switch {
+		case !shelfNamePattern.MatchString(shelf.Name):
+			return fmt.Errorf("shelf name %q must only contain lowercase letters, digits and dashes", shelf.Name)
+		case slices.Contains(reservedShelfNames, shelf.Name):
+			return fmt.Errorf("shelf name %q is reserved", shelf.Name)
+		case seen[shelf.Name]:
+			return fmt.Errorf("shelf name %q is used twice", shelf.Name)
+		case shelf.Data == "":
+			return fmt.Errorf("shelf %q has no data file", shelf.Name)
+		}
+		seen[shelf.Name] = true
+	}
+
+	return nil
+}
+
+func (c Config) validateBookshelfPage() error {
+	statuses := []string{dto.StatusReading, dto.StatusToRead, dto.StatusFinished}
+
+	if len(c.BookshelfPage.Sections) == 0 {
+		return fmt.Errorf("bookshelf page needs at least one section")
+	}
+	for i, section := range c.BookshelfPage.Sections {
+		switch {
+		case !slices.Contains(statuses, section):
+			return fmt.Errorf("bookshelf page section %q must be one of %s", section, strings.Join(statuses, ", "))
+		case slices.Contains(c.BookshelfPage.Sections[:i], section):
+			return fmt.Errorf("bookshelf page section %q is listed twice", section)
+		}
+	}
+
+	if !slices.Contains(dto.SortOrders, c.BookshelfPage.Sort) {
+		return fmt.Errorf("bookshelf page sort %q must be one of %s", c.BookshelfPage.Sort, strings.Join(dto.SortOrders, ", "))
+	}
+
+	return nil
+}
+
+// Name returns the name of the site as used in page titles and feeds, e.g.
+// "DT - My Digital Bookshelf".
+func (s Site) Name() string {
+	if s.Owner == "" {
+		return s.Title
+	}
+
+	return s.Owner + " - " + s.Title
+}
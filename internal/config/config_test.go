@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("could not write config file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{
+		"site": {"title": "Our Shelf", "owner": "AB", "nav": [{"label": "Home", "href": "index.html"}]},
+		"paths": {"output": "public"},
+		"features": {"api": false}
+	}`)
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+
+	if config.Site.Name() != "AB - Our Shelf" {
+		t.Errorf("expected site name 'AB - Our Shelf', got %s", config.Site.Name())
+	}
+
+	if len(config.Site.Nav) != 1 {
+		t.Errorf("expected the configured nav to replace the default one, got %d entries", len(config.Site.Nav))
+	}
+
+	if config.Paths.Output != "public" || config.Paths.Data != "data/data.json" {
+		t.Errorf("expected configured paths with defaults for missing ones, got %+v", config.Paths)
+	}
+
+	if config.Features.API || !config.Features.Feeds {
+		t.Errorf("expected only the api to be disabled, got %+v", config.Features)
+	}
+
+	if config.Site.Language != "en" {
+		t.Errorf("expected default language en, got %s", config.Site.Language)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	if _, err := Load(writeConfig(t, `{"site": `)); err == nil {
+		t.Error("expected an error for an invalid config file")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+}
+
+func TestSiteName(t *testing.T) {
+	if name := (Site{Title: "Bookshelf"}).Name(); name != "Bookshelf" {
+		t.Errorf("expected the title without an owner, got %s", name)
+	}
+}
+
+func TestLoad_Shelves(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"shelves": [
+		{"name": "alice", "owner": "Alice", "data": "data/alice.json"},
+		{"name": "bob", "data": "data/bob.json"}
+	]}`))
+	if err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+	if len(config.Shelves) != 2 || config.Shelves[1].Name != "bob" {
+		t.Errorf("expected two shelves, got %+v", config.Shelves)
+	}
+
+	tests := map[string]string{
+		"invalid name": `{"shelves": [{"name": "Alice", "data": "alice.json"}]}`,
+		"reserved":     `{"shelves": [{"name": "api", "data": "api.json"}]}`,
+		"duplicate":    `{"shelves": [{"name": "bob", "data": "a.json"}, {"name": "bob", "data": "b.json"}]}`,
+		"no data":      `{"shelves": [{"name": "bob"}]}`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("expected an error for invalid shelves")
+			}
+		})
+	}
+}
+
+func TestLoad_BookshelfPage(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"bookshelf_page": {"sections": ["finished", "reading"], "sort": "rating"}}`))
+	if err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+	if page := config.BookshelfPage; len(page.Sections) != 2 || page.Sections[0] != "finished" || page.Sort != "rating" {
+		t.Errorf("expected the configured bookshelf page, got %+v", page)
+	}
+
+	tests := map[string]string{
+		"no sections":     `{"bookshelf_page": {"sections": []}}`,
+		"unknown section": `{"bookshelf_page": {"sections": ["wishlisted"]}}`,
+		"duplicate":       `{"bookshelf_page": {"sections": ["reading", "reading"]}}`,
+		"unknown sort":    `{"bookshelf_page": {"sort": "color"}}`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("expected an error for an invalid bookshelf page")
+			}
+		})
+	}
+}
+
+func TestEnabledNav(t *testing.T) {
+	site := Default().Site
+	site.Nav = append(site.Nav, NavEntry{Label: "Other stats", Href: "https://example.com/stats.html"})
+
+	features := Default().Features
+	if nav := site.EnabledNav(features); len(nav) != len(site.Nav) {
+		t.Errorf("expected all entries with the stats feature, got %v", nav)
+	}
+
+	features.Stats = false
+	for _, entry := range site.EnabledNav(features) {
+		if entry.Href == "stats.html" {
+			t.Error("expected no link to the stats page without the stats feature")
+		}
+	}
+	if nav := site.EnabledNav(features); len(nav) != len(site.Nav)-1 {
+		t.Errorf("expected only the stats page to be left out, got %v", nav)
+	}
+}
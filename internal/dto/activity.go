@@ -0,0 +1,161 @@
+package dto
+
+import (
+	"sort"
+	"time"
+)
+
+const maxActivityLevel = 4
+
+// ReadingActivity returns the days books were read on, the current and longest
+// reading streak and a heatmap grid covering the given number of weeks.
+func (b *Bookshelf) ReadingActivity(weeks int) Activity {
+	today := b.today()
+	readingDays := b.readingDays(today)
+
+	activity := Activity{ActiveDays: len(readingDays)}
+	activity.CurrentStreak, activity.LongestStreak = b.streaks(readingDays, today)
+	activity.Weeks = b.activityWeeks(readingDays, today, weeks)
+
+	return activity
+}
+
+// readingDays counts the books read per day. Reading sessions are used where
+// present, otherwise every day between starting and finishing a book counts.
+func (b *Bookshelf) readingDays(today time.Time) map[string]int {
+	readingDays := make(map[string]int)
+
+	for _, book := range b.visibleBooks() {
+		// Wishlisted books are excluded
+		if book.Status == StatusWishlisted {
+			continue
+		}
+
+		if len(book.Progress.Sessions) > 0 {
+			for date := range b.sessionDays(book) {
+				readingDays[date]++
+			}
+			continue
+		}
+
+		for _, day := range b.readingSpan(book, today) {
+			readingDays[day.Format(dateLayout)]++
+		}
+	}
+
+	return readingDays
+}
+
+func (b *Bookshelf) sessionDays(book Book) map[string]bool {
+	sessionDays := make(map[string]bool, len(book.Progress.Sessions))
+
+	for _, session := range book.Progress.Sessions {
+		if day, ok := b.parseDate(session.Date); ok {
+			sessionDays[day.Format(dateLayout)] = true
+		}
+	}
+
+	return sessionDays
+}
+
+func (b *Bookshelf) readingSpan(book Book, today time.Time) []time.Time {
+	started, hasStarted := b.parseDate(book.Progress.DateStarted)
+	finished, hasFinished := b.parseDate(book.Progress.DateFinished)
+
+	// Books still being read are active until today
+	if !hasFinished && book.Status == StatusReading {
+		finished, hasFinished = today, true
+	}
+
+	switch {
+	case hasStarted && hasFinished && !finished.Before(started):
+		var span []time.Time
+		for day := started; !day.After(finished); day = day.AddDate(0, 0, 1) {
+			span = append(span, day)
+		}
+		return span
+	case hasStarted:
+		return []time.Time{started}
+	case hasFinished:
+		return []time.Time{finished}
+	}
+
+	return nil
+}
+
+func (b *Bookshelf) streaks(readingDays map[string]int, today time.Time) (current int, longest int) {
+	days := make([]string, 0, len(readingDays))
+	for day := range readingDays {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	var previous time.Time
+	run := 0
+	for _, date := range days {
+		day, _ := time.Parse(dateLayout, date)
+		if run > 0 && previous.AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+
+		longest = max(longest, run)
+		previous = day
+	}
+
+	// A streak is still current if nothing has been read yet today
+	day := today
+	if readingDays[day.Format(dateLayout)] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for readingDays[day.Format(dateLayout)] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return current, longest
+}
+
+// activityWeeks lays out the reading days as columns of weeks starting on
+// sunday, ending with the (partial) current week.
+func (b *Bookshelf) activityWeeks(readingDays map[string]int, today time.Time, weeks int) [][]ActivityDay {
+	if weeks <= 0 {
+		return nil
+	}
+
+	start := today.AddDate(0, 0, -(weeks-1)*7)
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+
+	var activityWeeks [][]ActivityDay
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Sunday {
+			activityWeeks = append(activityWeeks, make([]ActivityDay, 0, 7))
+		}
+
+		date := day.Format(dateLayout)
+		count := readingDays[date]
+		week := len(activityWeeks) - 1
+		activityWeeks[week] = append(activityWeeks[week], ActivityDay{
+			Date:  date,
+			Count: count,
+			Level: min(count, maxActivityLevel),
+		})
+	}
+
+	return activityWeeks
+}
+
+func (b *Bookshelf) today() time.Time {
+	t := now()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (b *Bookshelf) parseDate(date string) (time.Time, bool) {
+	parsedDate, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return parsedDate, true
+}
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"testing"
+)
+
+func TestReadingActivity(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	activity := bookshelf.ReadingActivity(4)
+
+	if activity.ActiveDays != 20 {
+		t.Errorf("expected 20 active days, got %d", activity.ActiveDays)
+	}
+
+	if activity.CurrentStreak != 20 {
+		t.Errorf("expected current streak of 20 days, got %d", activity.CurrentStreak)
+	}
+
+	if activity.LongestStreak != 20 {
+		t.Errorf("expected longest streak of 20 days, got %d", activity.LongestStreak)
+	}
+}
+
+func TestReadingActivity_Sessions(t *testing.T) {
+	bookshelf := &Bookshelf{
+		Books: []Book{
+			{
+				Id:     "book-1",
+				Status: StatusReading,
+				Progress: Progress{
+					DateStarted: "2025-11-01",
+					Sessions: []Session{
+						{Date: "2025-11-01", Pages: 20},
+						{Date: "2025-11-02", Pages: 30},
+						{Date: "2025-11-03", Pages: 10},
+						{Date: "2025-11-19", Pages: 25},
+						{Date: "2025-11-19", Pages: 5},
+					},
+				},
+			},
+			{
+				Id:       "book-2",
+				Status:   StatusFinished,
+				Progress: Progress{DateStarted: "2025-11-20", DateFinished: "2025-11-20"},
+			},
+		},
+	}
+
+	activity := bookshelf.ReadingActivity(1)
+
+	if activity.ActiveDays != 5 {
+		t.Errorf("expected 5 active days, got %d", activity.ActiveDays)
+	}
+
+	if activity.CurrentStreak != 2 {
+		t.Errorf("expected current streak of 2 days, got %d", activity.CurrentStreak)
+	}
+
+	if activity.LongestStreak != 3 {
+		t.Errorf("expected longest streak of 3 days, got %d", activity.LongestStreak)
+	}
+}
+
+func TestStreaks_CurrentStreakEndingYesterday(t *testing.T) {
+	bookshelf := Bookshelf{}
+	today := bookshelf.today()
+
+	readingDays := map[string]int{
+		"2025-11-17": 1,
+		"2025-11-18": 1,
+		"2025-11-19": 2,
+	}
+
+	current, longest := bookshelf.streaks(readingDays, today)
+
+	if current != 3 {
+		t.Errorf("expected current streak of 3 days, got %d", current)
+	}
+
+	if longest != 3 {
+		t.Errorf("expected longest streak of 3 days, got %d", longest)
+	}
+
+	current, _ = bookshelf.streaks(map[string]int{"2025-11-18": 1}, today)
+	if current != 0 {
+		t.Errorf("expected no current streak, got %d", current)
+	}
+}
+
+func TestActivityWeeks(t *testing.T) {
+	bookshelf := Bookshelf{}
+	today := bookshelf.today() // Thursday
+
+	weeks := bookshelf.activityWeeks(map[string]int{"2025-11-20": 7}, today, 2)
+
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+
+	if weeks[0][0].Date != "2025-11-09" {
+		t.Errorf("expected first week to start on sunday 2025-11-09, got %s", weeks[0][0].Date)
+	}
+
+	lastWeek := weeks[len(weeks)-1]
+	if len(lastWeek) != 5 {
+		t.Fatalf("expected 5 days in the current week, got %d", len(lastWeek))
+	}
+
+	if lastWeek[4].Count != 7 || lastWeek[4].Level != maxActivityLevel {
+		t.Errorf("expected count 7 with level %d, got count %d with level %d", maxActivityLevel, lastWeek[4].Count, lastWeek[4].Level)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"time"
 )
@@ -18,6 +20,14 @@ const (
 	StatusWishlisted = "wishlisted"
 )
 
+const (
+	dateLayout  = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+// now is the clock used for all date based calculations, replaceable in tests.
+var now = time.Now
+
 func LoadBookshelfFromFile(path string) (*Bookshelf, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -32,9 +42,85 @@ func LoadBookshelfFromFile(path string) (*Bookshelf, error) {
 	return &bookshelf, nil
 }
 
+// SaveBookshelfToFile writes the bookshelf as JSON, replacing the file only
+// once it is written completely.
+func SaveBookshelfToFile(path string, bookshelf *Bookshelf) error {
+	// Empty lists are written as [] rather than null
+	saved := *bookshelf
+	if saved.Books == nil {
+		saved.Books = []Book{}
+	}
+	if saved.Collections == nil {
+		saved.Collections = []Collection{}
+	}
+
+	data, err := json.MarshalIndent(&saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal JSON: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing JSON file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	// Temporary files are only readable by the owner, keep the mode of the
+	// replaced file instead
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	err = file.Chmod(mode)
+	if err == nil {
+		_, err = file.Write(append(data, '\n'))
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing JSON file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("writing JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of the bookshelf, e.g. to apply changes which may
+// be discarded.
+func (b *Bookshelf) Clone() *Bookshelf {
+	clone := &Bookshelf{
+		Books:          make([]Book, len(b.Books)),
+		Collections:    make([]Collection, len(b.Collections)),
+		includePrivate: b.includePrivate,
+	}
+
+	for i, book := range b.Books {
+		book.Authors = slices.Clone(book.Authors)
+		book.Tags = slices.Clone(book.Tags)
+		book.Review = slices.Clone(book.Review)
+		book.Quotes = slices.Clone(book.Quotes)
+		book.Progress.Sessions = slices.Clone(book.Progress.Sessions)
+		book.Forecast = nil
+		book.AlsoReadBy = nil
+		clone.Books[i] = book
+	}
+	for i, collection := range b.Collections {
+		collection.Books = slices.Clone(collection.Books)
+		clone.Collections[i] = collection
+	}
+
+	return clone
+}
+
 func (b *Bookshelf) bookById() map[string]Book {
-	bookById := make(map[string]Book, len(b.Books))
-	for _, book := range b.Books {
+	books := b.visibleBooks()
+	bookById := make(map[string]Book, len(books))
+	for _, book := range books {
 		bookById[book.Id] = book
 	}
 
@@ -44,7 +130,7 @@ func (b *Bookshelf) bookById() map[string]Book {
 func (b *Bookshelf) booksByStatus() map[string][]Book {
 	booksByStatus := make(map[string][]Book)
 
-	for _, book := range b.Books {
+	for _, book := range b.visibleBooks() {
 		booksByStatus[book.Status] = append(booksByStatus[book.Status], book)
 	}
 
@@ -57,6 +143,7 @@ func (b *Bookshelf) UpcomingBooks(limit int) (map[string][]Book, bool) {
 	hasUpcomingBooks := b.hasUpcomingBooks(booksByStatus)
 
 	b.sortBooksByRank(booksByStatus[StatusWishlisted])
+	b.addForecasts(booksByStatus[StatusReading])
 
 	if limit <= 0 {
 		return booksByStatus, hasUpcomingBooks
@@ -108,23 +195,30 @@ func (b *Bookshelf) ShelvedBooks() map[string][]Book {
 	for _, books := range shelvedBooks {
 		b.sortBooksAlphabetically(books)
 	}
+	b.addForecasts(shelvedBooks[StatusReading])
 
 	return shelvedBooks
 }
 
 func (b *Bookshelf) BookCollections() []ResolvedCollection {
 	bookByID := b.bookById()
+	hidden := b.hiddenBooks()
 	resolved := make([]ResolvedCollection, 0, len(b.Collections))
 
 	for _, c := range b.Collections {
 		var books []Book
-		for i, id := range c.Books {
+		for _, id := range c.Books {
 			if book, ok := bookByID[id]; ok {
-				book.Rank = i + 1
+				book.Rank = len(books) + 1
 				books = append(books, book)
 			}
 		}
 
+		// Collections of hidden books only would reveal their names
+		if len(books) == 0 && slices.ContainsFunc(c.Books, func(id string) bool { return hidden[id] }) {
+			continue
+		}
+
 		resolved = append(resolved, ResolvedCollection{
 			Name:        c.Name,
 			Description: c.Description,
@@ -138,10 +232,10 @@ func (b *Bookshelf) BookCollections() []ResolvedCollection {
 func (b *Bookshelf) BookQuotes() []Quote {
 	var quotes []Quote
 
-	for _, book := range b.Books {
+	for _, book := range b.visibleBooks() {
 		for _, quote := range book.Quotes {
 			quotes = append(quotes, Quote{
-				Quote:     quote,
+				Quote:     quote.Text,
 				Authors:   book.Authors,
 				BookTitle: book.Title,
 				Id:        book.Id,
@@ -168,7 +262,8 @@ func (b *Bookshelf) WishlistedBooks() []Book {
 
 func (b *Bookshelf) Stats() Stats {
 	var stats Stats
-	if len(b.Books) == 0 {
+	books := b.visibleBooks()
+	if len(books) == 0 {
 		return stats
 	}
 
@@ -178,10 +273,12 @@ func (b *Bookshelf) Stats() Stats {
 	genreCount := make(map[string]int)
 	languageCount := make(map[string]int)
 	statusCount := make(map[string]int)
+	monthCount := make(map[string]int)
+	ratingCount := make(map[string]int)
 
-	currentYear := time.Now().Year()
+	currentYear := now().Year()
 
-	for _, book := range b.Books {
+	for _, book := range books {
 		statusCount[book.Status]++
 
 		// Wishlisted books are excluded
@@ -199,6 +296,8 @@ func (b *Bookshelf) Stats() Stats {
 
 		genreCount = b.updateGenreCount(genreCount, book)
 		languageCount = b.updateLanguageCount(languageCount, book)
+		monthCount = b.updateMonthCount(monthCount, book)
+		ratingCount = b.updateRatingCount(ratingCount, book)
 	}
 
 	stats.AveragePages = b.calculateAverage(float64(totalPages), stats.TotalBooks)
@@ -207,6 +306,8 @@ func (b *Bookshelf) Stats() Stats {
 	stats.TopGenres = b.topGenres(genreCount, 3)
 	stats.BooksByLanguage = b.mapToStatCountSlice(languageCount)
 	stats.BooksByStatus = b.mapToStatCountSlice(statusCount)
+	stats.BooksPerMonth = b.booksPerMonth(monthCount, b.today(), 12)
+	stats.RatingDistribution = b.ratingDistribution(ratingCount)
 
 	return stats
 }
@@ -232,15 +333,20 @@ func (b *Bookshelf) sortBooksByRank(books []Book) {
 	})
 }
 
+// sortByCount orders by count, values with the same count are ordered by name
+// so the output of a build only changes when the data does.
 func (b *Bookshelf) sortByCount(s []StatCount) {
 	sort.SliceStable(s, func(i, j int) bool {
-		return s[i].Count > s[j].Count
+		if s[i].Count != s[j].Count {
+			return s[i].Count > s[j].Count
+		}
+		return s[i].Value < s[j].Value
 	})
 }
 
 func (b *Bookshelf) getYearFromDate(date string) int {
 	// Try to parse the date in "yyyy-mm-dd" format first
-	parsedDate, err := time.Parse("2006-01-02", date)
+	parsedDate, err := time.Parse(dateLayout, date)
 	if err == nil {
 		return parsedDate.Year()
 	}
@@ -254,6 +360,30 @@ func (b *Bookshelf) getYearFromDate(date string) int {
 	return parsedDate.Year()
 }
 
+// LastModified returns the most recent date any book was added, started or
+// finished, or an empty string if there is none.
+func (b *Bookshelf) LastModified() string {
+	lastModified := ""
+	for _, book := range b.visibleBooks() {
+		lastModified = max(lastModified, b.BookLastModified(book))
+	}
+
+	return lastModified
+}
+
+// BookLastModified returns the most recent date the book was added, started or
+// finished, or an empty string if there is none.
+func (b *Bookshelf) BookLastModified(book Book) string {
+	lastModified := ""
+	for _, date := range []string{book.DateAdded, book.Progress.DateStarted, book.Progress.DateFinished} {
+		if parsedDate, ok := b.parseDate(date); ok {
+			lastModified = max(lastModified, parsedDate.Format(dateLayout))
+		}
+	}
+
+	return lastModified
+}
+
 func (b *Bookshelf) updateStatsForFinishedBook(stats *Stats, book Book, currentYear int) {
 	if book.Status == StatusFinished {
 		stats.BooksFinished++
@@ -317,6 +447,31 @@ func (b *Bookshelf) updateLanguageCount(languageCount map[string]int, book Book)
 	return languageCount
 }
 
+func (b *Bookshelf) updateMonthCount(monthCount map[string]int, book Book) map[string]int {
+	if book.Status != StatusFinished {
+		return monthCount
+	}
+
+	if finished, ok := b.parseDate(book.Progress.DateFinished); ok {
+		monthCount[finished.Format(monthLayout)]++
+	}
+
+	return monthCount
+}
+
+func (b *Bookshelf) updateRatingCount(ratingCount map[string]int, book Book) map[string]int {
+	if book.Rating > 0 {
+		// Ratings are grouped into half star buckets
+		ratingCount[b.ratingBucket(book.Rating)]++
+	}
+
+	return ratingCount
+}
+
+func (b *Bookshelf) ratingBucket(rating float64) string {
+	return fmt.Sprintf("%.1f", math.Floor(rating*2)/2)
+}
+
 func (b *Bookshelf) calculateAverage(total float64, fraction int) float64 {
 	if fraction > 0 {
 		return math.Round(total/float64(fraction)*100) / 100
@@ -335,6 +490,33 @@ func (b *Bookshelf) topGenres(genreCount map[string]int, n int) []StatCount {
 	return statCount
 }
 
+// booksPerMonth returns the finished books of the n months up to lastMonth in
+// chronological order, including the months without any finished books.
+func (b *Bookshelf) booksPerMonth(monthCount map[string]int, lastMonth time.Time, n int) []StatCount {
+	stats := make([]StatCount, 0, n)
+	firstOfMonth := time.Date(lastMonth.Year(), lastMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := n - 1; i >= 0; i-- {
+		month := firstOfMonth.AddDate(0, -i, 0).Format(monthLayout)
+		stats = append(stats, StatCount{Value: month, Count: monthCount[month]})
+	}
+
+	return stats
+}
+
+// ratingDistribution returns the number of books per half star rating bucket,
+// including empty buckets so it can be shown as a histogram.
+func (b *Bookshelf) ratingDistribution(ratingCount map[string]int) []StatCount {
+	stats := make([]StatCount, 0, 10)
+
+	for rating := 0.5; rating <= 5; rating += 0.5 {
+		bucket := b.ratingBucket(rating)
+		stats = append(stats, StatCount{Value: bucket, Count: ratingCount[bucket]})
+	}
+
+	return stats
+}
+
 func (b *Bookshelf) mapToStatCountSlice(m map[string]int) []StatCount {
 	stats := make([]StatCount, 0, len(m))
 	for k, v := range m {
@@ -7,6 +7,14 @@ import (
 	"time"
 )
 
+func TestMain(m *testing.M) {
+	now = func() time.Time {
+		return time.Date(2025, time.November, 20, 12, 0, 0, 0, time.UTC)
+	}
+
+	os.Exit(m.Run())
+}
+
 func createTestBookshelf() *Bookshelf {
 	return &Bookshelf{
 		Books: []Book{
@@ -157,7 +165,7 @@ func TestUpdateStatsForFinishedBook(t *testing.T) {
 	bookshelf := createTestBookshelf()
 
 	stats := Stats{}
-	currentYear := time.Now().Year()
+	currentYear := now().Year()
 
 	for _, book := range bookshelf.Books {
 		bookshelf.updateStatsForFinishedBook(&stats, book, currentYear)
@@ -176,7 +184,7 @@ func TestUpdateStatsForPages(t *testing.T) {
 	bookshelf := createTestBookshelf()
 
 	stats := Stats{}
-	currentYear := time.Now().Year()
+	currentYear := now().Year()
 
 	for _, book := range bookshelf.Books {
 		bookshelf.updateStatsForPages(&stats, book, currentYear)
@@ -391,3 +399,55 @@ func TestGetYearFromDate(t *testing.T) {
 		})
 	}
 }
+
+func TestBooksPerMonth(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	stats := bookshelf.Stats()
+
+	if len(stats.BooksPerMonth) != 12 {
+		t.Fatalf("expected 12 months, got %d", len(stats.BooksPerMonth))
+	}
+
+	if stats.BooksPerMonth[0].Value != "2024-12" {
+		t.Errorf("expected first month to be 2024-12, got %s", stats.BooksPerMonth[0].Value)
+	}
+
+	currentMonth := stats.BooksPerMonth[11]
+	if currentMonth.Value != "2025-11" || currentMonth.Count != 2 {
+		t.Errorf("expected 2 books finished in 2025-11, got %d in %s", currentMonth.Count, currentMonth.Value)
+	}
+}
+
+func TestRatingDistribution(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	stats := bookshelf.Stats()
+
+	if len(stats.RatingDistribution) != 10 {
+		t.Fatalf("expected 10 rating buckets, got %d", len(stats.RatingDistribution))
+	}
+
+	expected := map[string]int{"3.5": 1, "4.0": 1, "4.5": 1, "5.0": 0}
+	for _, bucket := range stats.RatingDistribution {
+		if count, ok := expected[bucket.Value]; ok && bucket.Count != count {
+			t.Errorf("expected %d books rated %s, got %d", count, bucket.Value, bucket.Count)
+		}
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	if lastModified := bookshelf.LastModified(); lastModified != "2025-11-15" {
+		t.Errorf("expected the bookshelf to be last modified on 2025-11-15, got %s", lastModified)
+	}
+
+	if lastModified := bookshelf.BookLastModified(bookshelf.Books[0]); lastModified != "2025-11-14" {
+		t.Errorf("expected book-1 to be last modified on 2025-11-14, got %s", lastModified)
+	}
+
+	if lastModified := bookshelf.BookLastModified(Book{Progress: Progress{DateFinished: "2024"}}); lastModified != "" {
+		t.Errorf("expected no last modified date without exact dates, got %s", lastModified)
+	}
+}
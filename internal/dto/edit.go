@@ -0,0 +1,409 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrBookNotFound       = errors.New("book not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrQuoteNotFound      = errors.New("quote not found")
+)
+
+var bookIdPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// FieldError describes an invalid field of a book or collection.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists all invalid fields of a book or collection.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Field+" "+fieldError.Message)
+	}
+
+	return "invalid " + strings.Join(messages, ", ")
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: message})
+}
+
+func (e *ValidationError) orNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Book returns the book with the given id.
+func (b *Bookshelf) Book(id string) (Book, bool) {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return Book{}, false
+	}
+
+	return b.Books[i], true
+}
+
+// AddBook validates and adds a book, a book without id gets one derived from
+// its title. It returns the added book.
+func (b *Bookshelf) AddBook(book Book) (Book, error) {
+	if book.Id == "" {
+		book.Id = b.uniqueBookId(book.Title)
+	}
+
+	err := b.validateBook(book)
+	if err == nil && b.bookIndex(book.Id) >= 0 {
+		err = &ValidationError{Errors: []FieldError{{Field: "id", Message: "is already taken"}}}
+	}
+	if err != nil {
+		return Book{}, err
+	}
+
+	b.Books = append(b.Books, book)
+
+	return book, nil
+}
+
+// UpdateBook validates and replaces the book with the given id, the id of a
+// book can not be changed.
+func (b *Bookshelf) UpdateBook(id string, book Book) error {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return ErrBookNotFound
+	}
+
+	book.Id = id
+	err := b.validateBook(book)
+	if err != nil {
+		return err
+	}
+
+	b.Books[i] = book
+
+	return nil
+}
+
+// DeleteBook removes the book and all references to it from collections.
+func (b *Bookshelf) DeleteBook(id string) error {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return ErrBookNotFound
+	}
+
+	b.Books = slices.Delete(b.Books, i, i+1)
+	for j := range b.Collections {
+		b.Collections[j].Books = slices.DeleteFunc(b.Collections[j].Books, func(bookId string) bool {
+			return bookId == id
+		})
+	}
+
+	return nil
+}
+
+// UpdateProgress sets the pages read on the given date and records the pages
+// read since the last update as a reading session. Books are started when
+// reading begins and finished once all pages are read.
+func (b *Bookshelf) UpdateProgress(id string, pagesRead int, date string) error {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return ErrBookNotFound
+	}
+	book := b.Books[i]
+
+	validationErr := &ValidationError{}
+	if _, ok := b.parseDate(date); !ok {
+		validationErr.add("date", "must be a date in the format YYYY-MM-DD")
+	}
+	if pagesRead < 0 || (book.Pages > 0 && pagesRead > book.Pages) {
+		validationErr.add("pages_read", fmt.Sprintf("must be between 0 and %d", book.Pages))
+	}
+	if err := validationErr.orNil(); err != nil {
+		return err
+	}
+
+	if pages := pagesRead - book.Progress.PagesRead; pages > 0 {
+		book.Progress.Sessions = b.addSession(book.Progress.Sessions, Session{Date: date, Pages: pages})
+	}
+	book.Progress.PagesRead = pagesRead
+
+	if pagesRead > 0 && book.Progress.DateStarted == "" {
+		book.Progress.DateStarted = date
+	}
+	if pagesRead > 0 && (book.Status == StatusToRead || book.Status == StatusWishlisted) {
+		book.Status = StatusReading
+		book.Rank = 0
+	}
+	if book.Pages > 0 && pagesRead == book.Pages && book.Status != StatusFinished {
+		book.Status = StatusFinished
+		book.Progress.DateFinished = date
+	}
+
+	b.Books[i] = book
+
+	return nil
+}
+
+// addSession merges sessions on the same date.
+func (b *Bookshelf) addSession(sessions []Session, session Session) []Session {
+	for i := range sessions {
+		if sessions[i].Date == session.Date {
+			sessions[i].Pages += session.Pages
+			return sessions
+		}
+	}
+
+	return append(sessions, session)
+}
+
+// AddQuote adds a quote to the book.
+func (b *Bookshelf) AddQuote(id string, quote Entry) error {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return ErrBookNotFound
+	}
+
+	validationErr := &ValidationError{}
+	quote.Text = strings.TrimSpace(quote.Text)
+	if quote.Text == "" {
+		validationErr.add("quote", "is required")
+	}
+	if !slices.Contains(visibilities, quote.Visibility) {
+		validationErr.add("visibility", "must be one of public, private or draft")
+	}
+	if err := validationErr.orNil(); err != nil {
+		return err
+	}
+
+	b.Books[i].Quotes = append(b.Books[i].Quotes, quote)
+
+	return nil
+}
+
+// DeleteQuote removes the quote at the given index from the book.
+func (b *Bookshelf) DeleteQuote(id string, index int) error {
+	i := b.bookIndex(id)
+	if i < 0 {
+		return ErrBookNotFound
+	}
+	if index < 0 || index >= len(b.Books[i].Quotes) {
+		return ErrQuoteNotFound
+	}
+
+	b.Books[i].Quotes = slices.Delete(b.Books[i].Quotes, index, index+1)
+
+	return nil
+}
+
+// ReorderWishlist ranks the wishlisted books in the given order, it has to
+// contain all wishlisted books.
+func (b *Bookshelf) ReorderWishlist(ids []string) error {
+	wishlisted := make(map[string]int)
+	for i, book := range b.Books {
+		if book.Status == StatusWishlisted {
+			wishlisted[book.Id] = i
+		}
+	}
+
+	if len(ids) != len(wishlisted) {
+		return &ValidationError{Errors: []FieldError{{Field: "books", Message: "must list every wishlisted book once"}}}
+	}
+
+	for rank, id := range ids {
+		i, ok := wishlisted[id]
+		if !ok {
+			return &ValidationError{Errors: []FieldError{{Field: "books", Message: "must list every wishlisted book once"}}}
+		}
+		b.Books[i].Rank = rank + 1
+		delete(wishlisted, id)
+	}
+
+	return nil
+}
+
+// Collection returns the collection with the given name.
+func (b *Bookshelf) Collection(name string) (Collection, bool) {
+	i := b.collectionIndex(name)
+	if i < 0 {
+		return Collection{}, false
+	}
+
+	return b.Collections[i], true
+}
+
+func (b *Bookshelf) AddCollection(collection Collection) error {
+	err := b.validateCollection(collection)
+	if err == nil && b.collectionIndex(collection.Name) >= 0 {
+		err = &ValidationError{Errors: []FieldError{{Field: "name", Message: "is already taken"}}}
+	}
+	if err != nil {
+		return err
+	}
+
+	b.Collections = append(b.Collections, collection)
+
+	return nil
+}
+
+// UpdateCollection replaces the collection with the given name, it may be
+// renamed.
+func (b *Bookshelf) UpdateCollection(name string, collection Collection) error {
+	i := b.collectionIndex(name)
+	if i < 0 {
+		return ErrCollectionNotFound
+	}
+
+	err := b.validateCollection(collection)
+	if j := b.collectionIndex(collection.Name); err == nil && j >= 0 && j != i {
+		err = &ValidationError{Errors: []FieldError{{Field: "name", Message: "is already taken"}}}
+	}
+	if err != nil {
+		return err
+	}
+
+	b.Collections[i] = collection
+
+	return nil
+}
+
+func (b *Bookshelf) DeleteCollection(name string) error {
+	i := b.collectionIndex(name)
+	if i < 0 {
+		return ErrCollectionNotFound
+	}
+
+	b.Collections = slices.Delete(b.Collections, i, i+1)
+
+	return nil
+}
+
+func (b *Bookshelf) validateBook(book Book) error {
+	validationErr := &ValidationError{}
+
+	if !bookIdPattern.MatchString(book.Id) {
+		validationErr.add("id", "must only contain lowercase letters, digits and dashes")
+	}
+	if strings.TrimSpace(book.Title) == "" {
+		validationErr.add("title", "is required")
+	}
+	if !slices.Contains([]string{StatusFinished, StatusReading, StatusToRead, StatusWishlisted}, book.Status) {
+		validationErr.add("status", "must be one of finished, reading, to read or wishlisted")
+	}
+	if !slices.Contains(visibilities, book.Visibility) {
+		validationErr.add("visibility", "must be one of public, private or draft")
+	}
+	entries := []struct {
+		field   string
+		entries []Entry
+	}{
+		{"review", book.Review},
+		{"quotes", book.Quotes},
+	}
+	for _, list := range entries {
+		if slices.ContainsFunc(list.entries, func(entry Entry) bool { return !slices.Contains(visibilities, entry.Visibility) }) {
+			validationErr.add(list.field, "must only have paragraphs with a visibility of public, private or draft")
+		}
+	}
+	if book.Pages < 0 {
+		validationErr.add("pages", "must not be negative")
+	}
+	if book.Rank < 0 {
+		validationErr.add("rank", "must not be negative")
+	}
+	if book.Rating < 0 || book.Rating > 5 {
+		validationErr.add("rating", "must be between 0 and 5")
+	}
+	if book.Progress.PagesRead < 0 || (book.Pages > 0 && book.Progress.PagesRead > book.Pages) {
+		validationErr.add("pages_read", fmt.Sprintf("must be between 0 and %d", book.Pages))
+	}
+
+	dates := []struct {
+		field string
+		value string
+	}{
+		{"date_added", book.DateAdded},
+		{"date_started", book.Progress.DateStarted},
+		{"date_finished", book.Progress.DateFinished},
+	}
+	for _, date := range dates {
+		if date.value != "" && b.getYearFromDate(date.value) == 0 {
+			validationErr.add(date.field, "must be a date in the format YYYY-MM-DD or YYYY")
+		}
+	}
+
+	return validationErr.orNil()
+}
+
+func (b *Bookshelf) validateCollection(collection Collection) error {
+	validationErr := &ValidationError{}
+
+	if strings.TrimSpace(collection.Name) == "" {
+		validationErr.add("name", "is required")
+	}
+	for _, id := range collection.Books {
+		if b.bookIndex(id) < 0 {
+			validationErr.add("books", fmt.Sprintf("contains unknown book %s", id))
+		}
+	}
+
+	return validationErr.orNil()
+}
+
+func (b *Bookshelf) bookIndex(id string) int {
+	return slices.IndexFunc(b.Books, func(book Book) bool {
+		return book.Id == id
+	})
+}
+
+func (b *Bookshelf) collectionIndex(name string) int {
+	return slices.IndexFunc(b.Collections, func(collection Collection) bool {
+		return collection.Name == name
+	})
+}
+
+// uniqueBookId derives an id from the title like the existing ids, e.g.
+// "the-hitchhikers-guide-to-the-galaxy", numbered if it is already taken.
+func (b *Bookshelf) uniqueBookId(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	id := sb.String()
+	if id == "" {
+		id = "book"
+	}
+
+	unique := id
+	for n := 2; b.bookIndex(unique) >= 0; n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+
+	return unique
+}
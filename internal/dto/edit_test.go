@@ -0,0 +1,266 @@
+package dto
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func fieldErrors(t *testing.T, err error) []string {
+	t.Helper()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	var fields []string
+	for _, fieldError := range validationErr.Errors {
+		fields = append(fields, fieldError.Field)
+	}
+
+	return fields
+}
+
+func TestAddBook(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	book, err := bookshelf.AddBook(Book{Title: "The Hitchhiker's Guide to the Galaxy", Status: StatusToRead})
+	if err != nil {
+		t.Fatalf("could not add book: %v", err)
+	}
+	if book.Id != "the-hitchhikers-guide-to-the-galaxy" {
+		t.Errorf("expected id derived from title, got %s", book.Id)
+	}
+
+	book, err = bookshelf.AddBook(Book{Title: "The Hitchhiker's Guide to the Galaxy!", Status: StatusToRead})
+	if err != nil {
+		t.Fatalf("could not add book: %v", err)
+	}
+	if book.Id != "the-hitchhikers-guide-to-the-galaxy-2" {
+		t.Errorf("expected numbered id for a taken title, got %s", book.Id)
+	}
+
+	if _, ok := bookshelf.Book(book.Id); !ok {
+		t.Error("expected added book to be found")
+	}
+
+	_, err = bookshelf.AddBook(Book{Id: "book-1", Title: "Duplicate", Status: StatusToRead})
+	if fields := fieldErrors(t, err); !reflect.DeepEqual(fields, []string{"id"}) {
+		t.Errorf("expected taken id to be rejected, got %v", fields)
+	}
+
+	_, err = bookshelf.AddBook(Book{Id: "Invalid Id", Status: "lost", Rating: 6, Pages: 10, Progress: Progress{PagesRead: 20, DateStarted: "yesterday"}})
+	expected := []string{"id", "title", "status", "rating", "pages_read", "date_started"}
+	if fields := fieldErrors(t, err); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected invalid fields %v, got %v", expected, fields)
+	}
+}
+
+func TestUpdateBook(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	book, _ := bookshelf.Book("book-4")
+	book.Id = "renamed"
+	book.Title = "Book Four, Second Edition"
+
+	if err := bookshelf.UpdateBook("book-4", book); err != nil {
+		t.Fatalf("could not update book: %v", err)
+	}
+
+	updated, ok := bookshelf.Book("book-4")
+	if !ok || updated.Title != "Book Four, Second Edition" {
+		t.Errorf("expected book to be updated keeping its id, got %+v", updated)
+	}
+
+	if err := bookshelf.UpdateBook("missing", book); !errors.Is(err, ErrBookNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	book.Title = ""
+	if fields := fieldErrors(t, bookshelf.UpdateBook("book-4", book)); !reflect.DeepEqual(fields, []string{"title"}) {
+		t.Errorf("expected missing title to be rejected, got %v", fields)
+	}
+}
+
+func TestDeleteBook(t *testing.T) {
+	bookshelf := createTestBookshelf()
+	bookshelf.Collections = []Collection{{Name: "Favorites", Books: []string{"book-1", "book-2"}}}
+
+	if err := bookshelf.DeleteBook("book-1"); err != nil {
+		t.Fatalf("could not delete book: %v", err)
+	}
+
+	if _, ok := bookshelf.Book("book-1"); ok {
+		t.Error("expected book to be deleted")
+	}
+	if !reflect.DeepEqual(bookshelf.Collections[0].Books, []string{"book-2"}) {
+		t.Errorf("expected book to be removed from collections, got %v", bookshelf.Collections[0].Books)
+	}
+
+	if err := bookshelf.DeleteBook("book-1"); !errors.Is(err, ErrBookNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestUpdateProgress(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	// Starting a book to read
+	if err := bookshelf.UpdateProgress("book-4", 50, "2025-11-18"); err != nil {
+		t.Fatalf("could not update progress: %v", err)
+	}
+	if err := bookshelf.UpdateProgress("book-4", 120, "2025-11-18"); err != nil {
+		t.Fatalf("could not update progress: %v", err)
+	}
+	if err := bookshelf.UpdateProgress("book-4", 200, "2025-11-19"); err != nil {
+		t.Fatalf("could not update progress: %v", err)
+	}
+
+	book, _ := bookshelf.Book("book-4")
+	if book.Status != StatusReading || book.Progress.DateStarted != "2025-11-18" || book.Rank != 0 {
+		t.Errorf("expected book to be started, got %+v", book)
+	}
+
+	expectedSessions := []Session{{Date: "2025-11-18", Pages: 120}, {Date: "2025-11-19", Pages: 80}}
+	if !reflect.DeepEqual(book.Progress.Sessions, expectedSessions) {
+		t.Errorf("expected sessions %v, got %v", expectedSessions, book.Progress.Sessions)
+	}
+
+	// Reading the last page finishes the book
+	if err := bookshelf.UpdateProgress("book-4", 350, "2025-11-20"); err != nil {
+		t.Fatalf("could not update progress: %v", err)
+	}
+
+	book, _ = bookshelf.Book("book-4")
+	if book.Status != StatusFinished || book.Progress.DateFinished != "2025-11-20" {
+		t.Errorf("expected book to be finished, got %+v", book)
+	}
+
+	fields := fieldErrors(t, bookshelf.UpdateProgress("book-4", 400, "today"))
+	if !reflect.DeepEqual(fields, []string{"date", "pages_read"}) {
+		t.Errorf("expected invalid date and pages to be rejected, got %v", fields)
+	}
+}
+
+func TestAddQuote(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	if err := bookshelf.AddQuote("book-1", Entry{Text: "  A quote.  "}); err != nil {
+		t.Fatalf("could not add quote: %v", err)
+	}
+
+	book, _ := bookshelf.Book("book-1")
+	if !reflect.DeepEqual(book.Quotes, []Entry{{Text: "A quote."}}) {
+		t.Errorf("expected quote to be added, got %v", book.Quotes)
+	}
+
+	if fields := fieldErrors(t, bookshelf.AddQuote("book-1", Entry{Text: " "})); !reflect.DeepEqual(fields, []string{"quote"}) {
+		t.Errorf("expected empty quote to be rejected, got %v", fields)
+	}
+}
+
+func TestDeleteQuote(t *testing.T) {
+	bookshelf := createTestBookshelf()
+	bookshelf.Books[0].Quotes = []Entry{{Text: "First."}, {Text: "Second."}}
+
+	if err := bookshelf.DeleteQuote("book-1", 0); err != nil {
+		t.Fatalf("could not delete quote: %v", err)
+	}
+
+	book, _ := bookshelf.Book("book-1")
+	if !reflect.DeepEqual(book.Quotes, []Entry{{Text: "Second."}}) {
+		t.Errorf("expected first quote to be deleted, got %v", book.Quotes)
+	}
+
+	if err := bookshelf.DeleteQuote("book-1", 1); !errors.Is(err, ErrQuoteNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestReorderWishlist(t *testing.T) {
+	bookshelf := createTestBookshelf()
+	bookshelf.AddBook(Book{Id: "book-7", Title: "Book Seven", Status: StatusWishlisted, Rank: 1})
+
+	if err := bookshelf.ReorderWishlist([]string{"book-7", "book-5"}); err != nil {
+		t.Fatalf("could not reorder wishlist: %v", err)
+	}
+
+	var ids []string
+	for _, book := range bookshelf.WishlistedBooks() {
+		ids = append(ids, book.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"book-7", "book-5"}) {
+		t.Errorf("expected wishlist order [book-7 book-5], got %v", ids)
+	}
+
+	for _, order := range [][]string{{"book-7"}, {"book-7", "book-1"}, {"book-7", "book-7"}} {
+		if err := bookshelf.ReorderWishlist(order); err == nil {
+			t.Errorf("expected order %v to be rejected", order)
+		}
+	}
+}
+
+func TestCollections(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	if err := bookshelf.AddCollection(Collection{Name: "Favorites", Books: []string{"book-1"}}); err != nil {
+		t.Fatalf("could not add collection: %v", err)
+	}
+
+	if fields := fieldErrors(t, bookshelf.AddCollection(Collection{Name: "Favorites"})); !reflect.DeepEqual(fields, []string{"name"}) {
+		t.Errorf("expected taken name to be rejected, got %v", fields)
+	}
+	if fields := fieldErrors(t, bookshelf.AddCollection(Collection{Name: "Unknown", Books: []string{"missing"}})); !reflect.DeepEqual(fields, []string{"books"}) {
+		t.Errorf("expected unknown book to be rejected, got %v", fields)
+	}
+
+	if err := bookshelf.UpdateCollection("Favorites", Collection{Name: "All-time favorites", Books: []string{"book-1", "book-2"}}); err != nil {
+		t.Fatalf("could not update collection: %v", err)
+	}
+
+	collection, ok := bookshelf.Collection("All-time favorites")
+	if !ok || len(collection.Books) != 2 {
+		t.Errorf("expected collection to be renamed and updated, got %+v", collection)
+	}
+
+	if err := bookshelf.DeleteCollection("All-time favorites"); err != nil {
+		t.Fatalf("could not delete collection: %v", err)
+	}
+	if err := bookshelf.DeleteCollection("All-time favorites"); !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestSaveBookshelfToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	bookshelf := createTestBookshelf()
+	bookshelf.Collections = []Collection{{Name: "Favorites", Books: []string{"book-1"}}}
+
+	if err := SaveBookshelfToFile(path, bookshelf); err != nil {
+		t.Fatalf("could not save bookshelf: %v", err)
+	}
+
+	loaded, err := LoadBookshelfFromFile(path)
+	if err != nil {
+		t.Fatalf("could not load saved bookshelf: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, bookshelf) {
+		t.Errorf("expected saved bookshelf to be loaded unchanged, got %+v", loaded)
+	}
+}
+
+func TestClone(t *testing.T) {
+	bookshelf := createTestBookshelf()
+	bookshelf.Books[0].Quotes = []Entry{{Text: "A quote."}}
+
+	clone := bookshelf.Clone()
+	clone.Books[0].Quotes[0].Text = "Changed."
+	clone.Books[1].Title = "Changed"
+
+	if bookshelf.Books[0].Quotes[0].Text != "A quote." || bookshelf.Books[1].Title != "Book Two" {
+		t.Error("expected changes to the clone to leave the bookshelf unchanged")
+	}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"sort"
+)
+
+const (
+	FeedEntryFinished = "finished"
+	FeedEntryReview   = "review"
+	FeedEntryAdded    = "added"
+)
+
+// feedEntryOrder orders entries of the same day, a finished book comes before
+// its review and both before books added on that day.
+var feedEntryOrder = map[string]int{
+	FeedEntryFinished: 0,
+	FeedEntryReview:   1,
+	FeedEntryAdded:    2,
+}
+
+// FeedEntries returns the most recently finished books, reviews and added
+// books, newest first. Only entries with an exact date are included.
+func (b *Bookshelf) FeedEntries(limit int) []FeedEntry {
+	var entries []FeedEntry
+
+	for _, book := range b.visibleBooks() {
+		if finished, ok := b.parseDate(book.Progress.DateFinished); ok && book.Status == StatusFinished {
+			entries = append(entries, FeedEntry{Kind: FeedEntryFinished, Date: finished.Format(dateLayout), Book: book})
+
+			// Reviews have no date of their own, they are written when finishing a book
+			if len(book.Review) > 0 {
+				entries = append(entries, FeedEntry{Kind: FeedEntryReview, Date: finished.Format(dateLayout), Book: book})
+			}
+		}
+
+		if added, ok := b.parseDate(book.DateAdded); ok {
+			entries = append(entries, FeedEntry{Kind: FeedEntryAdded, Date: added.Format(dateLayout), Book: book})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date > entries[j].Date
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return feedEntryOrder[entries[i].Kind] < feedEntryOrder[entries[j].Kind]
+		}
+		return entries[i].Book.Id < entries[j].Book.Id
+	})
+
+	if limit > 0 && len(entries) > limit {
+		return entries[:limit]
+	}
+
+	return entries
+}
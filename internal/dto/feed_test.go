@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"testing"
+)
+
+func TestFeedEntries(t *testing.T) {
+	bookshelf := createTestBookshelf()
+	bookshelf.Books[0].Review = []Entry{{Text: "A great book."}}
+
+	entries := bookshelf.FeedEntries(0)
+
+	// 2 finished books with exact dates, 1 review and 6 added books
+	if len(entries) != 9 {
+		t.Fatalf("expected 9 feed entries, got %d", len(entries))
+	}
+
+	expected := []struct {
+		kind string
+		date string
+		id   string
+	}{
+		{FeedEntryFinished, "2025-11-14", "book-1"},
+		{FeedEntryReview, "2025-11-14", "book-1"},
+		{FeedEntryFinished, "2025-11-07", "book-2"},
+		{FeedEntryAdded, "2025-11-01", "book-1"},
+		{FeedEntryAdded, "2025-11-01", "book-2"},
+	}
+
+	for i, e := range expected {
+		if entries[i].Kind != e.kind || entries[i].Date != e.date || entries[i].Book.Id != e.id {
+			t.Errorf("expected %s of %s on %s at position %d, got %s of %s on %s",
+				e.kind, e.id, e.date, i, entries[i].Kind, entries[i].Book.Id, entries[i].Date)
+		}
+	}
+}
+
+func TestFeedEntries_Limit(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	entries := bookshelf.FeedEntries(3)
+
+	if len(entries) != 3 {
+		t.Errorf("expected 3 feed entries, got %d", len(entries))
+	}
+}
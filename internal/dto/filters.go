@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	FilterYear     = "year"
+	FilterGenre    = "genre"
+	FilterLanguage = "language"
+)
+
+// StatsByYear returns the stats of the books started or finished in each year,
+// with the most recent year first.
+func (b *Bookshelf) StatsByYear() []FilteredStats {
+	years := make(map[int]bool)
+	for _, book := range b.visibleBooks() {
+		for _, date := range []string{book.Progress.DateStarted, book.Progress.DateFinished} {
+			if year := b.getYearFromDate(date); year > 0 {
+				years[year] = true
+			}
+		}
+	}
+
+	sortedYears := make([]int, 0, len(years))
+	for year := range years {
+		sortedYears = append(sortedYears, year)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sortedYears)))
+
+	filteredStats := make([]FilteredStats, 0, len(sortedYears))
+	for _, year := range sortedYears {
+		filtered := b.filter(func(book Book) bool {
+			return b.getYearFromDate(book.Progress.DateStarted) == year ||
+				b.getYearFromDate(book.Progress.DateFinished) == year
+		})
+
+		// Show the months of the year itself instead of the last twelve months
+		stats := filtered.Stats()
+		stats.BooksPerMonth = filtered.booksPerMonth(filtered.monthCount(), time.Date(year, time.December, 1, 0, 0, 0, 0, time.UTC), 12)
+
+		filteredStats = append(filteredStats, FilteredStats{
+			Filter: FilterYear,
+			Value:  strconv.Itoa(year),
+			Stats:  stats,
+		})
+	}
+
+	return filteredStats
+}
+
+// StatsByGenre returns the stats of the books of each genre, ordered by genre.
+func (b *Bookshelf) StatsByGenre() []FilteredStats {
+	return b.statsBy(FilterGenre, func(book Book) string {
+		return book.Genre
+	})
+}
+
+// StatsByLanguage returns the stats of the books in each language, ordered by
+// language.
+func (b *Bookshelf) StatsByLanguage() []FilteredStats {
+	return b.statsBy(FilterLanguage, func(book Book) string {
+		return book.Language
+	})
+}
+
+func (b *Bookshelf) statsBy(filter string, valueOf func(Book) string) []FilteredStats {
+	values := make(map[string]bool)
+	for _, book := range b.visibleBooks() {
+		if value := valueOf(book); value != "" {
+			values[value] = true
+		}
+	}
+
+	sortedValues := make([]string, 0, len(values))
+	for value := range values {
+		sortedValues = append(sortedValues, value)
+	}
+	sort.Strings(sortedValues)
+
+	filteredStats := make([]FilteredStats, 0, len(sortedValues))
+	for _, value := range sortedValues {
+		filtered := b.filter(func(book Book) bool {
+			return valueOf(book) == value
+		})
+
+		filteredStats = append(filteredStats, FilteredStats{
+			Filter: filter,
+			Value:  value,
+			Stats:  filtered.Stats(),
+		})
+	}
+
+	return filteredStats
+}
+
+func (b *Bookshelf) filter(keep func(Book) bool) *Bookshelf {
+	filtered := &Bookshelf{Collections: b.Collections, includePrivate: b.includePrivate}
+
+	for _, book := range b.visibleBooks() {
+		if keep(book) {
+			filtered.Books = append(filtered.Books, book)
+		}
+	}
+
+	return filtered
+}
+
+func (b *Bookshelf) monthCount() map[string]int {
+	monthCount := make(map[string]int)
+	for _, book := range b.visibleBooks() {
+		monthCount = b.updateMonthCount(monthCount, book)
+	}
+
+	return monthCount
+}
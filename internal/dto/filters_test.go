@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"testing"
+)
+
+func TestStatsByYear(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	statsByYear := bookshelf.StatsByYear()
+
+	if len(statsByYear) != 2 {
+		t.Fatalf("expected stats for 2 years, got %d", len(statsByYear))
+	}
+
+	if statsByYear[0].Filter != FilterYear || statsByYear[0].Value != "2025" {
+		t.Errorf("expected the most recent year first, got %s %s", statsByYear[0].Filter, statsByYear[0].Value)
+	}
+
+	stats := statsByYear[0].Stats
+	if stats.TotalBooks != 3 || stats.BooksFinished != 2 {
+		t.Errorf("expected 3 books with 2 finished in 2025, got %d with %d finished", stats.TotalBooks, stats.BooksFinished)
+	}
+
+	if last := stats.BooksPerMonth[len(stats.BooksPerMonth)-1]; last.Value != "2025-12" {
+		t.Errorf("expected books per month to end in 2025-12, got %s", last.Value)
+	}
+
+	if november := stats.BooksPerMonth[10]; november.Count != 2 {
+		t.Errorf("expected 2 books finished in 2025-11, got %d", november.Count)
+	}
+
+	if statsByYear[1].Value != "2024" || statsByYear[1].Stats.TotalBooks != 1 {
+		t.Errorf("expected 1 book in 2024, got %d in %s", statsByYear[1].Stats.TotalBooks, statsByYear[1].Value)
+	}
+}
+
+func TestStatsByGenre(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	statsByGenre := bookshelf.StatsByGenre()
+
+	expected := []string{"fiction", "literature", "non-fiction", "science-fiction"}
+	if len(statsByGenre) != len(expected) {
+		t.Fatalf("expected stats for %d genres, got %d", len(expected), len(statsByGenre))
+	}
+
+	for i, genre := range expected {
+		if statsByGenre[i].Filter != FilterGenre || statsByGenre[i].Value != genre {
+			t.Errorf("expected genre %s at position %d, got %s", genre, i, statsByGenre[i].Value)
+		}
+	}
+
+	// Wishlisted books are excluded from the totals
+	if total := statsByGenre[3].Stats.TotalBooks; total != 1 {
+		t.Errorf("expected 1 science-fiction book, got %d", total)
+	}
+}
+
+func TestStatsByLanguage(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	statsByLanguage := bookshelf.StatsByLanguage()
+
+	if len(statsByLanguage) != 2 {
+		t.Fatalf("expected stats for 2 languages, got %d", len(statsByLanguage))
+	}
+
+	if statsByLanguage[0].Value != "de" || statsByLanguage[0].Stats.TotalBooks != 2 {
+		t.Errorf("expected 2 books in de, got %d in %s", statsByLanguage[0].Stats.TotalBooks, statsByLanguage[0].Value)
+	}
+
+	if statsByLanguage[0].Stats.AverageRating != 3.9 {
+		t.Errorf("expected an average rating of 3.9 for de, got %.2f", statsByLanguage[0].Stats.AverageRating)
+	}
+}
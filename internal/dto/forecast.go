@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"math"
+	"time"
+)
+
+// ToReadForecast estimates how long it takes to read all books on the to read
+// pile at the historical reading pace. It returns nil if there is no pace yet.
+func (b *Bookshelf) ToReadForecast() *Forecast {
+	pagesRemaining := 0
+	for _, book := range b.booksByStatus()[StatusToRead] {
+		pagesRemaining += book.Pages
+	}
+
+	return b.forecast(pagesRemaining, b.historicalPace())
+}
+
+// addForecasts sets the expected finish date of all books currently being read.
+func (b *Bookshelf) addForecasts(books []Book) {
+	historicalPace := b.historicalPace()
+
+	for i, book := range books {
+		if book.Status != StatusReading {
+			continue
+		}
+
+		pace := b.blendedPace(b.bookPace(book), historicalPace, book)
+		books[i].Forecast = b.forecast(book.Pages-book.Progress.PagesRead, pace)
+	}
+}
+
+func (b *Bookshelf) forecast(pagesRemaining int, pagesPerDay float64) *Forecast {
+	if pagesRemaining <= 0 || pagesPerDay <= 0 {
+		return nil
+	}
+
+	daysRemaining := int(math.Ceil(float64(pagesRemaining) / pagesPerDay))
+
+	return &Forecast{
+		PagesRemaining: pagesRemaining,
+		PagesPerDay:    math.Round(pagesPerDay*10) / 10,
+		DaysRemaining:  daysRemaining,
+		ExpectedFinish: b.today().AddDate(0, 0, daysRemaining).Format(dateLayout),
+	}
+}
+
+// historicalPace returns the average pages per day over all finished books
+// with exact start and finish dates.
+func (b *Bookshelf) historicalPace() float64 {
+	var pages, days int
+
+	for _, book := range b.visibleBooks() {
+		if book.Status != StatusFinished || book.Pages <= 0 {
+			continue
+		}
+
+		if readingDays, ok := b.readingDuration(book.Progress.DateStarted, book.Progress.DateFinished); ok {
+			pages += book.Pages
+			days += readingDays
+		}
+	}
+
+	if days == 0 {
+		return 0
+	}
+
+	return float64(pages) / float64(days)
+}
+
+// bookPace returns the pages per day read so far on a book currently being read.
+func (b *Bookshelf) bookPace(book Book) float64 {
+	if book.Progress.PagesRead <= 0 {
+		return 0
+	}
+
+	readingDays, ok := b.readingDuration(book.Progress.DateStarted, b.today().Format(dateLayout))
+	if !ok {
+		return 0
+	}
+
+	return float64(book.Progress.PagesRead) / float64(readingDays)
+}
+
+// blendedPace weighs the pace on the book itself by how far the book has been
+// read, so early estimates lean on the historical pace.
+func (b *Bookshelf) blendedPace(bookPace, historicalPace float64, book Book) float64 {
+	if historicalPace <= 0 || book.Pages <= 0 {
+		return bookPace
+	}
+
+	if bookPace <= 0 {
+		return historicalPace
+	}
+
+	progress := math.Min(float64(book.Progress.PagesRead)/float64(book.Pages), 1)
+
+	return progress*bookPace + (1-progress)*historicalPace
+}
+
+// readingDuration returns the number of days between both dates, counting the
+// start and finish day.
+func (b *Bookshelf) readingDuration(started, finished string) (int, bool) {
+	startDate, ok := b.parseDate(started)
+	if !ok {
+		return 0, false
+	}
+
+	finishDate, ok := b.parseDate(finished)
+	if !ok || finishDate.Before(startDate) {
+		return 0, false
+	}
+
+	return int(finishDate.Sub(startDate)/(24*time.Hour)) + 1, true
+}
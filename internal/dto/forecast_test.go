@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"testing"
+)
+
+func TestAddForecasts(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	shelvedBooks := bookshelf.ShelvedBooks()
+	reading := shelvedBooks[StatusReading]
+
+	if len(reading) != 1 || reading[0].Forecast == nil {
+		t.Fatal("expected a forecast for the book being read")
+	}
+
+	forecast := reading[0].Forecast
+	if forecast.PagesRemaining != 150 {
+		t.Errorf("expected 150 pages remaining, got %d", forecast.PagesRemaining)
+	}
+
+	if forecast.PagesPerDay != 26.2 {
+		t.Errorf("expected a pace of 26.2 pages per day, got %.1f", forecast.PagesPerDay)
+	}
+
+	if forecast.ExpectedFinish != "2025-11-26" {
+		t.Errorf("expected to finish on 2025-11-26, got %s", forecast.ExpectedFinish)
+	}
+
+	for _, book := range shelvedBooks[StatusFinished] {
+		if book.Forecast != nil {
+			t.Errorf("expected no forecast for finished book %s", book.Id)
+		}
+	}
+
+	if bookshelf.Books[2].Forecast != nil {
+		t.Error("expected the bookshelf itself to be left untouched")
+	}
+}
+
+func TestToReadForecast(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	forecast := bookshelf.ToReadForecast()
+	if forecast == nil {
+		t.Fatal("expected a forecast for the to read pile")
+	}
+
+	if forecast.PagesRemaining != 350 {
+		t.Errorf("expected 350 pages remaining, got %d", forecast.PagesRemaining)
+	}
+
+	if forecast.DaysRemaining != 11 {
+		t.Errorf("expected 11 days remaining, got %d", forecast.DaysRemaining)
+	}
+
+	if forecast.ExpectedFinish != "2025-12-01" {
+		t.Errorf("expected to finish on 2025-12-01, got %s", forecast.ExpectedFinish)
+	}
+}
+
+func TestToReadForecast_WithoutHistory(t *testing.T) {
+	bookshelf := &Bookshelf{
+		Books: []Book{{Id: "book-1", Pages: 100, Status: StatusToRead}},
+	}
+
+	if forecast := bookshelf.ToReadForecast(); forecast != nil {
+		t.Errorf("expected no forecast without a reading history, got %+v", forecast)
+	}
+}
+
+func TestBlendedPace(t *testing.T) {
+	bookshelf := Bookshelf{}
+	book := Book{Pages: 200, Progress: Progress{PagesRead: 100}}
+
+	if pace := bookshelf.blendedPace(10, 30, book); pace != 20 {
+		t.Errorf("expected a pace of 20 halfway through the book, got %.2f", pace)
+	}
+
+	if pace := bookshelf.blendedPace(0, 30, book); pace != 30 {
+		t.Errorf("expected the historical pace without progress, got %.2f", pace)
+	}
+
+	if pace := bookshelf.blendedPace(10, 0, book); pace != 10 {
+		t.Errorf("expected the book pace without history, got %.2f", pace)
+	}
+}
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"math"
+	"sort"
+)
+
+// Records collects the reading records of the bookshelf. Authors need at least
+// minAuthorBooks rated books to be considered for the highest rated author.
+func (b *Bookshelf) Records(minAuthorBooks int) Records {
+	var records Records
+
+	authorCount := make(map[string]int)
+	authorRatings := make(map[string][]float64)
+
+	for _, book := range b.visibleBooks() {
+		// Wishlisted books are excluded
+		if book.Status == StatusWishlisted {
+			continue
+		}
+
+		records.MostQuotedBook = b.maxRecord(records.MostQuotedBook, book, float64(len(book.Quotes)))
+
+		if book.Status != StatusFinished {
+			continue
+		}
+
+		for _, author := range book.Authors {
+			authorCount[author]++
+			if book.Rating > 0 {
+				authorRatings[author] = append(authorRatings[author], book.Rating)
+			}
+		}
+
+		records.LongestBook = b.maxRecord(records.LongestBook, book, float64(book.Pages))
+
+		if readingDays, ok := b.readingDuration(book.Progress.DateStarted, book.Progress.DateFinished); ok && book.Pages > 0 {
+			pagesPerDay := math.Round(float64(book.Pages)/float64(readingDays)*10) / 10
+			records.FastestRead = b.maxRecord(records.FastestRead, book, pagesPerDay)
+		}
+
+		if book.Year > 0 && (records.OldestBook == nil || float64(book.Year) < records.OldestBook.Value) {
+			records.OldestBook = &BookRecord{Book: book, Value: float64(book.Year)}
+		}
+	}
+
+	records.MostReadAuthors = b.topAuthors(authorCount, 5)
+	records.HighestRatedAuthor = b.highestRatedAuthor(authorRatings, minAuthorBooks)
+	records.AverageDaysToStart = b.averageDaysToStart()
+
+	return records
+}
+
+// maxRecord returns the record with the higher value, keeping the current one
+// on ties so the first book in the data wins.
+func (b *Bookshelf) maxRecord(current *BookRecord, book Book, value float64) *BookRecord {
+	if value <= 0 || (current != nil && value <= current.Value) {
+		return current
+	}
+
+	return &BookRecord{Book: book, Value: value}
+}
+
+func (b *Bookshelf) topAuthors(authorCount map[string]int, n int) []StatCount {
+	authors := b.mapToStatCountSlice(authorCount)
+
+	// Authors with the same count are ordered by name to keep the order stable
+	sort.SliceStable(authors, func(i, j int) bool {
+		if authors[i].Count != authors[j].Count {
+			return authors[i].Count > authors[j].Count
+		}
+		return authors[i].Value < authors[j].Value
+	})
+
+	if len(authors) > n {
+		return authors[:n]
+	}
+
+	return authors
+}
+
+func (b *Bookshelf) highestRatedAuthor(authorRatings map[string][]float64, minBooks int) *AuthorRecord {
+	var best *AuthorRecord
+
+	for author, ratings := range authorRatings {
+		if len(ratings) < max(minBooks, 1) {
+			continue
+		}
+
+		total := 0.0
+		for _, rating := range ratings {
+			total += rating
+		}
+		averageRating := b.calculateAverage(total, len(ratings))
+
+		if best == nil || averageRating > best.AverageRating ||
+			(averageRating == best.AverageRating && author < best.Author) {
+			best = &AuthorRecord{Author: author, Books: len(ratings), AverageRating: averageRating}
+		}
+	}
+
+	return best
+}
+
+// averageDaysToStart returns the average number of days books waited on the
+// shelf between being added and being started.
+func (b *Bookshelf) averageDaysToStart() float64 {
+	var totalDays, books int
+
+	for _, book := range b.visibleBooks() {
+		if days, ok := b.readingDuration(book.DateAdded, book.Progress.DateStarted); ok {
+			// readingDuration counts both days, waiting time does not
+			totalDays += days - 1
+			books++
+		}
+	}
+
+	return b.calculateAverage(float64(totalDays), books)
+}
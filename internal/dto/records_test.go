@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"testing"
+)
+
+func createRecordsTestBookshelf() *Bookshelf {
+	return &Bookshelf{
+		Books: []Book{
+			{
+				Id:        "book-1",
+				Authors:   []string{"Author A"},
+				Year:      1949,
+				Pages:     300,
+				Status:    StatusFinished,
+				Rating:    4.5,
+				Progress:  Progress{DateStarted: "2025-11-08", DateFinished: "2025-11-14"},
+				DateAdded: "2025-11-01",
+				Quotes:    []Entry{{Text: "quote 1"}},
+			},
+			{
+				Id:        "book-2",
+				Authors:   []string{"Author A", "Author B"},
+				Year:      1968,
+				Pages:     150,
+				Status:    StatusFinished,
+				Rating:    3.5,
+				Progress:  Progress{DateStarted: "2025-11-01", DateFinished: "2025-11-03"},
+				DateAdded: "2025-11-01",
+				Quotes:    []Entry{{Text: "quote 2"}, {Text: "quote 3"}},
+			},
+			{
+				Id:        "book-3",
+				Authors:   []string{"Author C"},
+				Year:      1932,
+				Pages:     500,
+				Status:    StatusReading,
+				Progress:  Progress{DateStarted: "2025-11-15", PagesRead: 50},
+				DateAdded: "2025-11-05",
+				Quotes:    []Entry{{Text: "quote 4"}, {Text: "quote 5"}, {Text: "quote 6"}},
+			},
+			{
+				Id:        "book-4",
+				Authors:   []string{"Author B"},
+				Year:      2001,
+				Pages:     200,
+				Status:    StatusFinished,
+				Rating:    4.8,
+				Progress:  Progress{DateStarted: "2024", DateFinished: "2024"},
+				DateAdded: "2024-01-01",
+			},
+			{
+				Id:      "book-5",
+				Authors: []string{"Author D"},
+				Year:    1800,
+				Pages:   1000,
+				Status:  StatusWishlisted,
+				Quotes:  []Entry{{Text: "quote 7"}, {Text: "quote 8"}, {Text: "quote 9"}, {Text: "quote 10"}},
+			},
+		},
+	}
+}
+
+func TestRecords(t *testing.T) {
+	bookshelf := createRecordsTestBookshelf()
+
+	records := bookshelf.Records(2)
+
+	if len(records.MostReadAuthors) != 2 {
+		t.Fatalf("expected 2 authors, got %d", len(records.MostReadAuthors))
+	}
+
+	if records.MostReadAuthors[0].Value != "Author A" || records.MostReadAuthors[0].Count != 2 {
+		t.Errorf("expected Author A with 2 books to be the most read author, got %+v", records.MostReadAuthors[0])
+	}
+
+	if records.MostReadAuthors[1].Value != "Author B" {
+		t.Errorf("expected Author B to be the second most read author, got %s", records.MostReadAuthors[1].Value)
+	}
+
+	if records.HighestRatedAuthor == nil || records.HighestRatedAuthor.Author != "Author B" {
+		t.Fatalf("expected Author B to be the highest rated author, got %+v", records.HighestRatedAuthor)
+	}
+
+	if records.HighestRatedAuthor.AverageRating != 4.15 {
+		t.Errorf("expected an average rating of 4.15, got %.2f", records.HighestRatedAuthor.AverageRating)
+	}
+
+	if records.LongestBook == nil || records.LongestBook.Book.Id != "book-1" {
+		t.Errorf("expected book-1 to be the longest finished book, got %+v", records.LongestBook)
+	}
+
+	if records.FastestRead == nil || records.FastestRead.Book.Id != "book-2" || records.FastestRead.Value != 50 {
+		t.Errorf("expected book-2 with 50 pages per day to be the fastest read, got %+v", records.FastestRead)
+	}
+
+	if records.OldestBook == nil || records.OldestBook.Book.Id != "book-1" {
+		t.Errorf("expected book-1 to be the oldest book read, got %+v", records.OldestBook)
+	}
+
+	if records.MostQuotedBook == nil || records.MostQuotedBook.Book.Id != "book-3" || records.MostQuotedBook.Value != 3 {
+		t.Errorf("expected book-3 with 3 quotes to be the most quoted book, got %+v", records.MostQuotedBook)
+	}
+
+	// book-1: 7 days, book-2: 0 days, book-3: 10 days
+	if records.AverageDaysToStart != 5.67 {
+		t.Errorf("expected an average of 5.67 days to start, got %.2f", records.AverageDaysToStart)
+	}
+}
+
+func TestRecords_MinAuthorBooks(t *testing.T) {
+	bookshelf := createRecordsTestBookshelf()
+
+	if author := bookshelf.Records(3).HighestRatedAuthor; author != nil {
+		t.Errorf("expected no author with 3 rated books, got %+v", author)
+	}
+
+	if author := bookshelf.Records(1).HighestRatedAuthor; author == nil || author.Author != "Author B" {
+		t.Errorf("expected Author B to be the highest rated author, got %+v", author)
+	}
+}
+
+func TestRecords_Empty(t *testing.T) {
+	bookshelf := &Bookshelf{}
+
+	records := bookshelf.Records(2)
+
+	if records.LongestBook != nil || records.FastestRead != nil || records.OldestBook != nil || records.MostQuotedBook != nil {
+		t.Errorf("expected no book records for an empty bookshelf, got %+v", records)
+	}
+}
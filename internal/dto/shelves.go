@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"sort"
+	"strings"
+)
+
+// Shelf is the bookshelf of one member of a site with several shelves, Name
+// is the directory the pages of the shelf are rendered to.
+type Shelf struct {
+	Name      string
+	Owner     string
+	Bookshelf *Bookshelf
+}
+
+// LinkSharedBooks sets AlsoReadBy of all books to the members of the other
+// shelves who finished a book with the same ISBN.
+func LinkSharedBooks(shelves []Shelf) {
+	readers := readersByIsbn(shelves)
+
+	for _, shelf := range shelves {
+		for i, book := range shelf.Bookshelf.Books {
+			book.AlsoReadBy = nil
+			for _, reader := range readers[normalizeIsbn(book.Isbn)] {
+				if reader.Shelf != shelf.Name {
+					book.AlsoReadBy = append(book.AlsoReadBy, reader)
+				}
+			}
+			shelf.Bookshelf.Books[i] = book
+		}
+	}
+}
+
+// SharedBooks returns the books finished by more than one member, the most
+// read first.
+func SharedBooks(shelves []Shelf) []SharedBook {
+	readers := readersByIsbn(shelves)
+
+	var shared []SharedBook
+	seen := make(map[string]bool)
+	for _, shelf := range shelves {
+		for _, book := range shelf.Bookshelf.visibleBooks() {
+			isbn := normalizeIsbn(book.Isbn)
+			if len(readers[isbn]) < 2 || seen[isbn] {
+				continue
+			}
+
+			seen[isbn] = true
+			book.AlsoReadBy = nil
+			shared = append(shared, SharedBook{Book: book, Readers: readers[isbn]})
+		}
+	}
+
+	sort.SliceStable(shared, func(i, j int) bool {
+		if len(shared[i].Readers) != len(shared[j].Readers) {
+			return len(shared[i].Readers) > len(shared[j].Readers)
+		}
+		return shared[i].Book.Title < shared[j].Book.Title
+	})
+
+	return shared
+}
+
+// readersByIsbn returns the members who finished a book by its normalized
+// ISBN, in the order of the shelves.
+func readersByIsbn(shelves []Shelf) map[string][]Reader {
+	readers := make(map[string][]Reader)
+
+	for _, shelf := range shelves {
+		for _, book := range shelf.Bookshelf.visibleBooks() {
+			isbn := normalizeIsbn(book.Isbn)
+			if isbn == "" || book.Status != StatusFinished {
+				continue
+			}
+
+			readers[isbn] = append(readers[isbn], Reader{Owner: shelf.Owner, Shelf: shelf.Name, BookId: book.Id})
+		}
+	}
+
+	return readers
+}
+
+// normalizeIsbn removes the separators of an ISBN, e.g. "978-0-451-45799-8".
+func normalizeIsbn(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createTestShelves() []Shelf {
+	return []Shelf{
+		{Name: "alice", Owner: "Alice", Bookshelf: &Bookshelf{Books: []Book{
+			{Id: "dune", Isbn: "978-0-441-17271-9", Title: "Dune", Status: StatusFinished},
+			{Id: "emma", Isbn: "9780141439587", Title: "Emma", Status: StatusFinished},
+			{Id: "no-isbn", Title: "No ISBN", Status: StatusFinished},
+		}}},
+		{Name: "bob", Owner: "Bob", Bookshelf: &Bookshelf{Books: []Book{
+			{Id: "dune-1965", Isbn: "9780441172719", Title: "Dune", Status: StatusFinished},
+			{Id: "emma", Isbn: "9780141439587", Title: "Emma", Status: StatusReading},
+			{Id: "no-isbn", Title: "No ISBN", Status: StatusFinished},
+		}}},
+		{Name: "carol", Owner: "Carol", Bookshelf: &Bookshelf{Books: []Book{
+			{Id: "dune", Isbn: "978 0441 172719", Title: "Dune", Status: StatusFinished},
+		}}},
+	}
+}
+
+func TestLinkSharedBooks(t *testing.T) {
+	shelves := createTestShelves()
+	LinkSharedBooks(shelves)
+
+	expected := []Reader{{Owner: "Bob", Shelf: "bob", BookId: "dune-1965"}, {Owner: "Carol", Shelf: "carol", BookId: "dune"}}
+	if got := shelves[0].Bookshelf.Books[0].AlsoReadBy; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected readers %v, got %v", expected, got)
+	}
+
+	// Books being read count as not read yet
+	if got := shelves[1].Bookshelf.Books[1].AlsoReadBy; !reflect.DeepEqual(got, []Reader{{Owner: "Alice", Shelf: "alice", BookId: "emma"}}) {
+		t.Errorf("expected Alice to have read Emma, got %v", got)
+	}
+	if got := shelves[0].Bookshelf.Books[1].AlsoReadBy; got != nil {
+		t.Errorf("expected no other readers of Emma, got %v", got)
+	}
+
+	// Books without ISBN are never shared
+	if got := shelves[0].Bookshelf.Books[2].AlsoReadBy; got != nil {
+		t.Errorf("expected books without ISBN not to be linked, got %v", got)
+	}
+}
+
+func TestSharedBooks(t *testing.T) {
+	shared := SharedBooks(createTestShelves())
+
+	if len(shared) != 1 || shared[0].Book.Title != "Dune" || len(shared[0].Readers) != 3 {
+		t.Errorf("expected Dune to be read by 3 members, got %+v", shared)
+	}
+}
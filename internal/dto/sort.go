@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const (
+	SortByTitle        = "title"
+	SortByAuthor       = "author"
+	SortByRating       = "rating"
+	SortByDateFinished = "date_finished"
+	SortByPages        = "pages"
+	SortByYear         = "year"
+)
+
+// SortOrders are the orders books can be sorted in.
+var SortOrders = []string{SortByTitle, SortByAuthor, SortByRating, SortByDateFinished, SortByPages, SortByYear}
+
+// bookComparators compare two books in a sort order, ratings, finish dates,
+// pages and years from the highest down, so books without one come last.
+var bookComparators = map[string]func(a, b Book) int{
+	SortByTitle: func(a, b Book) int { return 0 },
+	SortByAuthor: func(a, b Book) int {
+		// Books without authors come last
+		if (len(a.Authors) == 0) != (len(b.Authors) == 0) {
+			return cmp.Compare(len(b.Authors), len(a.Authors))
+		}
+		return strings.Compare(authorSortKey(a), authorSortKey(b))
+	},
+	SortByRating:       func(a, b Book) int { return cmp.Compare(b.Rating, a.Rating) },
+	SortByDateFinished: func(a, b Book) int { return strings.Compare(b.Progress.DateFinished, a.Progress.DateFinished) },
+	SortByPages:        func(a, b Book) int { return cmp.Compare(b.Pages, a.Pages) },
+	SortByYear:         func(a, b Book) int { return cmp.Compare(b.Year, a.Year) },
+}
+
+// SortBooks sorts the books in one of the SortOrders, authors by the last name
+// of the first author. Books which rank the same are sorted by title.
+func (b *Bookshelf) SortBooks(books []Book, order string) {
+	compare, ok := bookComparators[order]
+	if !ok {
+		compare = bookComparators[SortByTitle]
+	}
+
+	slices.SortStableFunc(books, func(x, y Book) int {
+		return cmp.Or(compare(x, y), strings.Compare(x.Title, y.Title))
+	})
+}
+
+// authorSortKey returns the first author as "last name, first names".
+func authorSortKey(book Book) string {
+	if len(book.Authors) == 0 {
+		return ""
+	}
+
+	names := strings.Fields(strings.ToLower(book.Authors[0]))
+	if len(names) == 0 {
+		return ""
+	}
+
+	return names[len(names)-1] + ", " + strings.Join(names[:len(names)-1], " ")
+}
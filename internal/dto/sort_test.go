@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortBooks(t *testing.T) {
+	books := []Book{
+		{Id: "a", Title: "Alpha", Authors: []string{"Ursula K. Le Guin"}, Rating: 4, Pages: 200, Year: 1969, Progress: Progress{DateFinished: "2025-03-01"}},
+		{Id: "b", Title: "Beta", Authors: []string{"Iain Banks"}, Rating: 4.5, Pages: 500, Year: 1987},
+		{Id: "c", Title: "Gamma", Rating: 4, Pages: 300, Year: 2001, Progress: Progress{DateFinished: "2025-05-01"}},
+		{Id: "d", Title: "Delta", Authors: []string{"Anne Leckie"}, Pages: 100, Year: 2013, Progress: Progress{DateFinished: "2024-12-01"}},
+	}
+
+	tests := map[string][]string{
+		SortByTitle:        {"a", "b", "d", "c"},
+		SortByAuthor:       {"b", "a", "d", "c"},
+		SortByRating:       {"b", "a", "c", "d"},
+		SortByDateFinished: {"c", "a", "d", "b"},
+		SortByPages:        {"b", "c", "a", "d"},
+		SortByYear:         {"d", "c", "b", "a"},
+		"unknown":          {"a", "b", "d", "c"},
+	}
+
+	bookshelf := &Bookshelf{}
+	for order, expected := range tests {
+		t.Run(order, func(t *testing.T) {
+			sorted := append([]Book(nil), books...)
+			bookshelf.SortBooks(sorted, order)
+
+			var ids []string
+			for _, book := range sorted {
+				ids = append(ids, book.Id)
+			}
+			if !reflect.DeepEqual(ids, expected) {
+				t.Errorf("expected %v, got %v", expected, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package dto
+
+// Store gives access to the bookshelf in server mode, changes are written
+// back to the data file.
+type Store interface {
+	// Bookshelf returns the current bookshelf, it must not be modified
+	Bookshelf() *Bookshelf
+
+	// Update applies the changes to a copy of the bookshelf and saves it,
+	// the bookshelf stays unchanged if update returns an error
+	Update(update func(bookshelf *Bookshelf) error) error
+}
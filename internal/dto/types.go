@@ -5,34 +5,48 @@ package dto
 type Bookshelf struct {
 	Books       []Book       `json:"books"`
 	Collections []Collection `json:"collections"`
+
+	// includePrivate makes the methods take private and draft books and
+	// entries into account, see IncludingPrivate
+	includePrivate bool
 }
 
 type Book struct {
-	Id        string   `json:"id"`
-	Isbn      string   `json:"isbn"`
-	Title     string   `json:"title"`
-	Subtitle  string   `json:"subtitle"`
-	Authors   []string `json:"authors"`
-	Year      int      `json:"year"`
-	Language  string   `json:"language"`
-	Pages     int      `json:"pages"`
-	Genre     string   `json:"genre"`
-	Tags      []string `json:"tags"`
-	Cover     string   `json:"cover"`
-	Link      string   `json:"link"`
-	DateAdded string   `json:"date_added"`
-	Status    string   `json:"status"`
-	Rank      int      `json:"rank"`
-	Progress  Progress `json:"progress"`
-	Rating    float64  `json:"rating"`
-	Review    []string `json:"review"`
-	Quotes    []string `json:"quotes"`
+	Id         string   `json:"id"`
+	Isbn       string   `json:"isbn"`
+	Title      string   `json:"title"`
+	Subtitle   string   `json:"subtitle"`
+	Authors    []string `json:"authors"`
+	Year       int      `json:"year"`
+	Language   string   `json:"language"`
+	Pages      int      `json:"pages"`
+	Genre      string   `json:"genre"`
+	Tags       []string `json:"tags"`
+	Cover      string   `json:"cover"`
+	Link       string   `json:"link"`
+	DateAdded  string   `json:"date_added"`
+	Status     string   `json:"status"`
+	Visibility string   `json:"visibility,omitempty"`
+	Rank       int      `json:"rank,omitempty"`
+	Progress   Progress `json:"progress"`
+	Rating     float64  `json:"rating,omitempty"`
+	Review     []Entry  `json:"review,omitempty"`
+	Quotes     []Entry  `json:"quotes,omitempty"`
+
+	Forecast   *Forecast `json:"-"`
+	AlsoReadBy []Reader  `json:"-"`
 }
 
 type Progress struct {
-	DateStarted  string `json:"date_started"`
-	DateFinished string `json:"date_finished"`
-	PagesRead    int    `json:"pages_read"`
+	DateStarted  string    `json:"date_started,omitempty"`
+	DateFinished string    `json:"date_finished,omitempty"`
+	PagesRead    int       `json:"pages_read,omitempty"`
+	Sessions     []Session `json:"sessions,omitempty"`
+}
+
+type Session struct {
+	Date  string `json:"date"`
+	Pages int    `json:"pages"`
 }
 
 type Collection struct {
@@ -54,6 +68,61 @@ type Stats struct {
 	TopGenres             []StatCount
 	BooksByStatus         []StatCount
 	BooksByLanguage       []StatCount
+	BooksPerMonth         []StatCount
+	RatingDistribution    []StatCount
+}
+
+type Activity struct {
+	CurrentStreak int
+	LongestStreak int
+	ActiveDays    int
+	Weeks         [][]ActivityDay
+}
+
+type ActivityDay struct {
+	Date  string
+	Count int
+	Level int
+}
+
+type Forecast struct {
+	PagesRemaining int
+	PagesPerDay    float64
+	DaysRemaining  int
+	ExpectedFinish string
+}
+
+type Records struct {
+	MostReadAuthors    []StatCount
+	HighestRatedAuthor *AuthorRecord
+	LongestBook        *BookRecord
+	FastestRead        *BookRecord
+	OldestBook         *BookRecord
+	MostQuotedBook     *BookRecord
+	AverageDaysToStart float64
+}
+
+type AuthorRecord struct {
+	Author        string
+	Books         int
+	AverageRating float64
+}
+
+type BookRecord struct {
+	Book  Book
+	Value float64
+}
+
+type FilteredStats struct {
+	Filter string
+	Value  string
+	Stats  Stats
+}
+
+type FeedEntry struct {
+	Kind string
+	Date string
+	Book Book
 }
 
 type StatCount struct {
@@ -67,6 +136,19 @@ type ResolvedCollection struct {
 	Books       []Book
 }
 
+// Reader is a member of a site with several shelves who read a book, BookId
+// is the id of the book on the reader's shelf.
+type Reader struct {
+	Owner  string
+	Shelf  string
+	BookId string
+}
+
+type SharedBook struct {
+	Book    Book
+	Readers []Reader
+}
+
 type Quote struct {
 	Quote     string
 	Authors   []string
@@ -0,0 +1,174 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+	VisibilityDraft   = "draft"
+)
+
+var visibilities = []string{"", VisibilityPublic, VisibilityPrivate, VisibilityDraft}
+
+// Entry is a paragraph of a review or a quote. Public entries are stored as
+// plain strings, others as an object with their visibility, e.g.
+// {"text": "...", "visibility": "private"}.
+type Entry struct {
+	Text       string `json:"text"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// entry has the fields of Entry without its JSON methods.
+type entry Entry
+
+func (e Entry) String() string {
+	return e.Text
+}
+
+// IsPublic reports whether the entry is published, entries without a
+// visibility are public.
+func (e Entry) IsPublic() bool {
+	return isPublic(e.Visibility)
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	if e.IsPublic() {
+		return json.Marshal(e.Text)
+	}
+
+	return json.Marshal(entry(e))
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*e = Entry{}
+		return json.Unmarshal(data, &e.Text)
+	}
+
+	return json.Unmarshal(data, (*entry)(e))
+}
+
+// Texts returns the texts of the entries.
+func Texts(entries []Entry) []string {
+	if entries == nil {
+		return nil
+	}
+
+	texts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		texts = append(texts, entry.Text)
+	}
+
+	return texts
+}
+
+// IsPublic reports whether the book is published, books without a visibility
+// are public.
+func (b Book) IsPublic() bool {
+	return isPublic(b.Visibility)
+}
+
+func isPublic(visibility string) bool {
+	return visibility == "" || visibility == VisibilityPublic
+}
+
+// Public returns a copy of the bookshelf with everything that is published:
+// private and draft books are removed from the books and collections, private
+// and draft paragraphs of reviews and quotes from the books, e.g. for pages
+// which range over the books themselves.
+func (b *Bookshelf) Public() *Bookshelf {
+	clone := b.Clone()
+	public := &Bookshelf{}
+
+	hidden := make(map[string]bool)
+	for _, book := range clone.Books {
+		if !book.IsPublic() {
+			hidden[book.Id] = true
+			continue
+		}
+
+		book.Review = publicEntries(book.Review)
+		book.Quotes = publicEntries(book.Quotes)
+		public.Books = append(public.Books, book)
+	}
+
+	for _, collection := range clone.Collections {
+		empty := len(collection.Books) == 0
+		collection.Books = slices.DeleteFunc(collection.Books, func(id string) bool { return hidden[id] })
+
+		// Collections of hidden books only would reveal their names
+		if len(collection.Books) > 0 || empty {
+			public.Collections = append(public.Collections, collection)
+		}
+	}
+
+	return public
+}
+
+// IncludingPrivate returns the bookshelf with its methods, e.g. Stats or
+// BookQuotes, taking private and draft books, review paragraphs and quotes
+// into account. They leave them out otherwise. It is meant for the admin area
+// and local previews.
+func (b *Bookshelf) IncludingPrivate() *Bookshelf {
+	including := *b
+	including.includePrivate = true
+
+	return &including
+}
+
+// visibleBooks returns the books the methods of the bookshelf take into
+// account: the public books with their public review paragraphs and quotes,
+// or all books once private ones are included.
+func (b *Bookshelf) visibleBooks() []Book {
+	if b.includePrivate {
+		return b.Books
+	}
+
+	books := make([]Book, 0, len(b.Books))
+	for _, book := range b.Books {
+		if !book.IsPublic() {
+			continue
+		}
+
+		// The entries are shared with the bookshelf, only copy them to
+		// remove private ones
+		if !allPublic(book.Review) {
+			book.Review = publicEntries(slices.Clone(book.Review))
+		}
+		if !allPublic(book.Quotes) {
+			book.Quotes = publicEntries(slices.Clone(book.Quotes))
+		}
+		books = append(books, book)
+	}
+
+	return books
+}
+
+// hiddenBooks returns the ids of the books the methods of the bookshelf leave
+// out.
+func (b *Bookshelf) hiddenBooks() map[string]bool {
+	hidden := make(map[string]bool)
+	if b.includePrivate {
+		return hidden
+	}
+
+	for _, book := range b.Books {
+		if !book.IsPublic() {
+			hidden[book.Id] = true
+		}
+	}
+
+	return hidden
+}
+
+func allPublic(entries []Entry) bool {
+	return !slices.ContainsFunc(entries, func(entry Entry) bool { return !entry.IsPublic() })
+}
+
+func publicEntries(entries []Entry) []Entry {
+	return slices.DeleteFunc(entries, func(entry Entry) bool { return !entry.IsPublic() })
+}
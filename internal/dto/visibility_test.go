@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEntryJSON(t *testing.T) {
+	var book Book
+	err := json.Unmarshal([]byte(`{"quotes": ["Public.", {"text": "Private.", "visibility": "private"}]}`), &book)
+	if err != nil {
+		t.Fatalf("could not unmarshal book: %v", err)
+	}
+
+	expected := []Entry{{Text: "Public."}, {Text: "Private.", Visibility: VisibilityPrivate}}
+	if !reflect.DeepEqual(book.Quotes, expected) {
+		t.Errorf("expected quotes %v, got %v", expected, book.Quotes)
+	}
+
+	data, err := json.Marshal(book.Quotes)
+	if err != nil {
+		t.Fatalf("could not marshal quotes: %v", err)
+	}
+	if string(data) != `["Public.",{"text":"Private.","visibility":"private"}]` {
+		t.Errorf("expected public quotes to be stored as strings, got %s", data)
+	}
+}
+
+func createPrivateBookshelf() *Bookshelf {
+	bookshelf := createTestBookshelf()
+	bookshelf.Books[0].Review = []Entry{{Text: "Public."}, {Text: "Draft.", Visibility: VisibilityDraft}}
+	bookshelf.Books[0].Quotes = []Entry{{Text: "Private.", Visibility: VisibilityPrivate}}
+	bookshelf.Books[1].Visibility = VisibilityPrivate
+	bookshelf.Books[1].Quotes = []Entry{{Text: "Quote of a private book."}}
+	bookshelf.Books[2].Visibility = VisibilityDraft
+	bookshelf.Books[3].Visibility = VisibilityPublic
+	bookshelf.Collections = []Collection{
+		{Name: "Private", Books: []string{"book-2"}},
+		{Name: "Mixed", Books: []string{"book-2", "book-4"}},
+		{Name: "Empty"},
+	}
+
+	return bookshelf
+}
+
+func TestPublic(t *testing.T) {
+	bookshelf := createPrivateBookshelf()
+	public := bookshelf.Public()
+
+	var ids []string
+	for _, book := range public.Books {
+		ids = append(ids, book.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"book-1", "book-4", "book-5", "book-6"}) {
+		t.Errorf("expected private and draft books to be removed, got %v", ids)
+	}
+
+	if !reflect.DeepEqual(public.Books[0].Review, []Entry{{Text: "Public."}}) || len(public.Books[0].Quotes) != 0 {
+		t.Errorf("expected private and draft entries to be removed, got %v and %v", public.Books[0].Review, public.Books[0].Quotes)
+	}
+
+	expected := []Collection{{Name: "Mixed", Books: []string{"book-4"}}, {Name: "Empty"}}
+	if !reflect.DeepEqual(public.Collections, expected) {
+		t.Errorf("expected collections %v, got %v", expected, public.Collections)
+	}
+
+	if len(bookshelf.Books) != 6 || len(bookshelf.Books[0].Review) != 2 {
+		t.Error("expected the bookshelf to be unchanged")
+	}
+}
+
+func TestPublic_Methods(t *testing.T) {
+	bookshelves := map[string]*Bookshelf{
+		"public":     createPrivateBookshelf().Public(),
+		"unfiltered": createPrivateBookshelf(),
+	}
+
+	for name, bookshelf := range bookshelves {
+		t.Run(name, func(t *testing.T) {
+			if stats := bookshelf.Stats(); stats.TotalBooks != 3 || stats.BooksFinished != 2 {
+				t.Errorf("expected stats of the public books only, got %d books and %d finished", stats.TotalBooks, stats.BooksFinished)
+			}
+
+			shelved := bookshelf.ShelvedBooks()
+			if len(shelved[StatusFinished]) != 2 || len(shelved[StatusReading]) != 0 {
+				t.Errorf("expected shelves without private and draft books, got %v", shelved)
+			}
+
+			if upcoming, _ := bookshelf.UpcomingBooks(5); len(upcoming[StatusReading]) != 0 {
+				t.Errorf("expected no upcoming draft books, got %v", upcoming[StatusReading])
+			}
+
+			if collections := bookshelf.BookCollections(); len(collections) != 2 || len(collections[0].Books) != 1 || collections[0].Books[0].Rank != 1 {
+				t.Errorf("expected collections of public books only, got %v", collections)
+			}
+
+			if quotes := bookshelf.BookQuotes(); len(quotes) != 0 {
+				t.Errorf("expected no private quotes, got %v", quotes)
+			}
+		})
+	}
+
+	bookshelf := createPrivateBookshelf()
+	including := bookshelf.IncludingPrivate()
+	if stats := including.Stats(); stats.TotalBooks != 5 {
+		t.Errorf("expected stats of all books, got %d books", stats.TotalBooks)
+	}
+	if quotes := including.BookQuotes(); len(quotes) != 2 {
+		t.Errorf("expected private quotes, got %v", quotes)
+	}
+	if collections := including.BookCollections(); len(collections) != 3 {
+		t.Errorf("expected all collections, got %v", collections)
+	}
+	if len(bookshelf.BookQuotes()) != 0 || len(bookshelf.Books[0].Quotes) != 1 {
+		t.Error("expected private books to be an opt-in which leaves the bookshelf unchanged")
+	}
+}
+
+func TestValidateVisibility(t *testing.T) {
+	bookshelf := createTestBookshelf()
+
+	book := bookshelf.Books[0]
+	book.Visibility = "secret"
+	book.Quotes = []Entry{{Text: "A quote.", Visibility: "hidden"}}
+
+	if fields := fieldErrors(t, bookshelf.UpdateBook(book.Id, book)); !reflect.DeepEqual(fields, []string{"visibility", "quotes"}) {
+		t.Errorf("expected invalid visibilities to be rejected, got %v", fields)
+	}
+
+	if fields := fieldErrors(t, bookshelf.AddQuote(book.Id, Entry{Text: "A quote.", Visibility: "hidden"})); !reflect.DeepEqual(fields, []string{"visibility"}) {
+		t.Errorf("expected invalid visibility of a quote to be rejected, got %v", fields)
+	}
+}
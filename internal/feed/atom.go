@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string    `xml:"title"`
+	ID      string    `xml:"id"`
+	Updated string    `xml:"updated"`
+	Link    atomLink  `xml:"link"`
+	Summary string    `xml:"summary,omitempty"`
+	Content *atomText `xml:"content,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom encodes the feed as an Atom 1.0 document, selfURL is the absolute URL
+// the document is published at.
+func Atom(f Feed, selfURL string) ([]byte, error) {
+	feed := atomFeed{
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.Link,
+		Updated:  f.updated().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	if f.Author != "" {
+		feed.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, entry := range f.Entries {
+		atomEntry := atomEntry{
+			Title:   entry.Title,
+			ID:      entry.ID,
+			Updated: entry.Updated.Format(time.RFC3339),
+			Link:    atomLink{Href: entry.Link, Rel: "alternate", Type: "text/html"},
+			Summary: entry.Summary,
+		}
+
+		if entry.Content != "" {
+			atomEntry.Content = &atomText{Type: "html", Body: entry.Content}
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry)
+	}
+
+	return encode(feed)
+}
@@ -0,0 +1,46 @@
+// Package feed encodes a list of entries as Atom and RSS feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	Author      string
+	Entries     []Entry
+}
+
+type Entry struct {
+	ID      string
+	Title   string
+	Link    string
+	Updated time.Time
+	Summary string
+	Content string // HTML, escaped when encoding the feed
+}
+
+// updated returns the date of the newest entry, so feeds only change when the
+// entries do.
+func (f Feed) updated() time.Time {
+	var updated time.Time
+	for _, entry := range f.Entries {
+		if entry.Updated.After(updated) {
+			updated = entry.Updated
+		}
+	}
+
+	return updated
+}
+
+func encode(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
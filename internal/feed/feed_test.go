@@ -0,0 +1,149 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestFeed() Feed {
+	return Feed{
+		Title:       "My Bookshelf",
+		Description: "Books & more",
+		Link:        "https://example.com/bookshelf/index.html",
+		Author:      "DT",
+		Entries: []Entry{
+			{
+				ID:      "https://example.com/bookshelf/book-1.html#review-2025-11-14",
+				Title:   "Review: Book <One>",
+				Link:    "https://example.com/bookshelf/book-1.html",
+				Updated: time.Date(2025, time.November, 14, 0, 0, 0, 0, time.UTC),
+				Summary: "Book One by Author A",
+				Content: "<p>Loved it &amp; would read again</p>",
+			},
+			{
+				ID:      "https://example.com/bookshelf/book-2.html#added-2025-11-01",
+				Title:   "Added: Book Two",
+				Link:    "https://example.com/bookshelf/book-2.html",
+				Updated: time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC),
+				Summary: "Book Two by Author B",
+			},
+		},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	data, err := Atom(createTestFeed(), "https://example.com/bookshelf/feed.xml")
+	if err != nil {
+		t.Fatalf("could not encode atom feed: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("could not decode atom feed: %v", err)
+	}
+
+	if feed.Updated != "2025-11-14T00:00:00Z" {
+		t.Errorf("expected feed to be updated with the newest entry, got %s", feed.Updated)
+	}
+
+	if len(feed.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(feed.Entries))
+	}
+
+	if feed.Entries[0].Title != "Review: Book <One>" {
+		t.Errorf("expected title to survive encoding, got %s", feed.Entries[0].Title)
+	}
+
+	if feed.Entries[0].Content == nil || feed.Entries[0].Content.Body != "<p>Loved it &amp; would read again</p>" {
+		t.Errorf("expected HTML content to survive encoding, got %+v", feed.Entries[0].Content)
+	}
+
+	if feed.Entries[1].Content != nil {
+		t.Error("expected no content for entries without content")
+	}
+
+	if strings.Contains(string(data), "<p>") {
+		t.Error("expected HTML content to be escaped")
+	}
+}
+
+func TestRSS(t *testing.T) {
+	data, err := RSS(createTestFeed(), "https://example.com/bookshelf/rss.xml")
+	if err != nil {
+		t.Fatalf("could not encode rss feed: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("could not decode rss feed: %v", err)
+	}
+
+	items := feed.Channel.Items
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0].PubDate != "Fri, 14 Nov 2025 00:00:00 +0000" {
+		t.Errorf("expected an RFC 1123 publication date, got %s", items[0].PubDate)
+	}
+
+	if items[0].Description != "<p>Loved it &amp; would read again</p>" {
+		t.Errorf("expected the content as description, got %s", items[0].Description)
+	}
+
+	if items[1].Description != "Book Two by Author B" {
+		t.Errorf("expected the summary as description, got %s", items[1].Description)
+	}
+
+	if !strings.Contains(string(data), `<atom:link href="https://example.com/bookshelf/rss.xml" rel="self"`) {
+		t.Error("expected a self link")
+	}
+}
+
+func TestAtom_Deterministic(t *testing.T) {
+	first, _ := Atom(createTestFeed(), "https://example.com/bookshelf/feed.xml")
+	second, _ := Atom(createTestFeed(), "https://example.com/bookshelf/feed.xml")
+
+	if string(first) != string(second) {
+		t.Error("expected the same feed to be encoded identically")
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	data, err := JSONFeed(createTestFeed(), "https://example.com/bookshelf/feed.json")
+	if err != nil {
+		t.Fatalf("could not encode json feed: %v", err)
+	}
+
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("could not decode json feed: %v", err)
+	}
+
+	if feed.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("expected JSON Feed 1.1, got %s", feed.Version)
+	}
+
+	if feed.FeedURL != "https://example.com/bookshelf/feed.json" {
+		t.Errorf("expected the feed url, got %s", feed.FeedURL)
+	}
+
+	if len(feed.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(feed.Items))
+	}
+
+	if feed.Items[0].ContentHTML == "" || feed.Items[0].ContentText != "" {
+		t.Errorf("expected HTML content for entries with content, got %+v", feed.Items[0])
+	}
+
+	if feed.Items[1].ContentText != "Book Two by Author B" {
+		t.Errorf("expected the summary as text content, got %+v", feed.Items[1])
+	}
+
+	if feed.Items[0].DatePublished != "2025-11-14T00:00:00Z" {
+		t.Errorf("expected an RFC 3339 date, got %s", feed.Items[0].DatePublished)
+	}
+}
@@ -0,0 +1,74 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Description string           `json:"description,omitempty"`
+	Authors     []jsonFeedAuthor `json:"authors,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary,omitempty"`
+	ContentHTML   string `json:"content_html,omitempty"`
+	ContentText   string `json:"content_text,omitempty"`
+	DatePublished string `json:"date_published"`
+}
+
+// JSONFeed encodes the feed as a JSON Feed 1.1 document, selfURL is the
+// absolute URL the document is published at.
+func JSONFeed(f Feed, selfURL string) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     selfURL,
+		Description: f.Description,
+		Items:       make([]jsonFeedItem, 0, len(f.Entries)),
+	}
+
+	if f.Author != "" {
+		feed.Authors = []jsonFeedAuthor{{Name: f.Author}}
+	}
+
+	for _, entry := range f.Entries {
+		item := jsonFeedItem{
+			ID:            entry.ID,
+			URL:           entry.Link,
+			Title:         entry.Title,
+			Summary:       entry.Summary,
+			ContentHTML:   entry.Content,
+			DatePublished: entry.Updated.Format(time.RFC3339),
+		}
+
+		// Every item requires either HTML or text content
+		if item.ContentHTML == "" {
+			item.ContentText = entry.Summary
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,141 @@
+// Package jsonld describes books, reviews and lists of books as schema.org
+// structured data. The values are meant to be embedded into a
+// <script type="application/ld+json"> element by html/template, which encodes
+// them as JSON safe to be used within HTML.
+package jsonld
+
+import (
+	"strconv"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+const context = "https://schema.org"
+
+// URLFunc returns the absolute URL of a path relative to the site.
+type URLFunc func(path string) string
+
+type Book struct {
+	Context       string   `json:"@context,omitempty"`
+	Type          string   `json:"@type"`
+	Name          string   `json:"name"`
+	Alternate     string   `json:"alternativeHeadline,omitempty"`
+	URL           string   `json:"url,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	Author        []Person `json:"author,omitempty"`
+	Isbn          string   `json:"isbn,omitempty"`
+	NumberOfPages int      `json:"numberOfPages,omitempty"`
+	InLanguage    string   `json:"inLanguage,omitempty"`
+	DatePublished string   `json:"datePublished,omitempty"`
+	Genre         string   `json:"genre,omitempty"`
+	Keywords      string   `json:"keywords,omitempty"`
+	Review        *Review  `json:"review,omitempty"`
+}
+
+type Person struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type Review struct {
+	Type         string  `json:"@type"`
+	Author       *Person `json:"author,omitempty"`
+	ReviewRating *Rating `json:"reviewRating,omitempty"`
+	ReviewBody   string  `json:"reviewBody,omitempty"`
+}
+
+type Rating struct {
+	Type        string  `json:"@type"`
+	RatingValue float64 `json:"ratingValue"`
+	BestRating  float64 `json:"bestRating"`
+	WorstRating float64 `json:"worstRating"`
+}
+
+type ItemList struct {
+	Context         string     `json:"@context"`
+	Type            string     `json:"@type"`
+	Name            string     `json:"name,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	NumberOfItems   int        `json:"numberOfItems"`
+	ItemListElement []ListItem `json:"itemListElement"`
+}
+
+type ListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+}
+
+// NewBook describes a book, including its review if it was rated or reviewed.
+// The reviewer is the name of the person the review is attributed to.
+func NewBook(book dto.Book, url URLFunc, reviewer string) Book {
+	structuredBook := Book{
+		Context:       context,
+		Type:          "Book",
+		Name:          book.Title,
+		Alternate:     book.Subtitle,
+		URL:           url(book.Id + ".html"),
+		Image:         book.Cover,
+		Isbn:          book.Isbn,
+		NumberOfPages: book.Pages,
+		InLanguage:    book.Language,
+		Genre:         book.Genre,
+		Keywords:      strings.Join(book.Tags, ", "),
+	}
+
+	if book.Year > 0 {
+		structuredBook.DatePublished = strconv.Itoa(book.Year)
+	}
+
+	for _, author := range book.Authors {
+		structuredBook.Author = append(structuredBook.Author, Person{Type: "Person", Name: author})
+	}
+
+	if book.Rating > 0 || len(book.Review) > 0 {
+		structuredBook.Review = newReview(book, reviewer)
+	}
+
+	return structuredBook
+}
+
+func newReview(book dto.Book, reviewer string) *Review {
+	review := &Review{
+		Type:       "Review",
+		ReviewBody: strings.Join(dto.Texts(book.Review), "\n\n"),
+	}
+
+	if reviewer != "" {
+		review.Author = &Person{Type: "Person", Name: reviewer}
+	}
+
+	if book.Rating > 0 {
+		review.ReviewRating = &Rating{Type: "Rating", RatingValue: book.Rating, BestRating: 5, WorstRating: 0.5}
+	}
+
+	return review
+}
+
+// NewItemList describes a list of books in the given order.
+func NewItemList(name, description string, books []dto.Book, url URLFunc) ItemList {
+	itemList := ItemList{
+		Context:         context,
+		Type:            "ItemList",
+		Name:            name,
+		Description:     description,
+		NumberOfItems:   len(books),
+		ItemListElement: make([]ListItem, 0, len(books)),
+	}
+
+	for i, book := range books {
+		itemList.ItemListElement = append(itemList.ItemListElement, ListItem{
+			Type:     "ListItem",
+			Position: i + 1,
+			URL:      url(book.Id + ".html"),
+			Name:     book.Title,
+		})
+	}
+
+	return itemList
+}
@@ -0,0 +1,109 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+	"testing"
+
+	"bookshelf/internal/dto"
+)
+
+func testURL(path string) string {
+	return "https://example.com/" + path
+}
+
+func TestNewBook(t *testing.T) {
+	book := dto.Book{
+		Id:       "book-1",
+		Isbn:     "9780000000001",
+		Title:    "Book One",
+		Authors:  []string{"Author A", "Author B"},
+		Year:     1949,
+		Language: "en",
+		Pages:    300,
+		Rating:   4.5,
+		Review:   []dto.Entry{{Text: "First paragraph."}, {Text: "Second paragraph."}},
+	}
+
+	data, err := json.Marshal(NewBook(book, testURL, "DT"))
+	if err != nil {
+		t.Fatalf("could not marshal book: %v", err)
+	}
+
+	var structured map[string]any
+	if err := json.Unmarshal(data, &structured); err != nil {
+		t.Fatalf("could not unmarshal book: %v", err)
+	}
+
+	expected := map[string]any{
+		"@context":      "https://schema.org",
+		"@type":         "Book",
+		"name":          "Book One",
+		"isbn":          "9780000000001",
+		"numberOfPages": float64(300),
+		"inLanguage":    "en",
+		"datePublished": "1949",
+		"url":           "https://example.com/book-1.html",
+	}
+	for key, value := range expected {
+		if structured[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, structured[key])
+		}
+	}
+
+	if authors := structured["author"].([]any); len(authors) != 2 {
+		t.Errorf("expected 2 authors, got %d", len(authors))
+	}
+
+	review := structured["review"].(map[string]any)
+	if review["reviewBody"] != "First paragraph.\n\nSecond paragraph." {
+		t.Errorf("expected the review paragraphs as review body, got %v", review["reviewBody"])
+	}
+
+	rating := review["reviewRating"].(map[string]any)
+	if rating["ratingValue"] != 4.5 || rating["bestRating"] != float64(5) {
+		t.Errorf("expected a rating of 4.5 out of 5, got %v", rating)
+	}
+}
+
+func TestNewBook_WithoutReview(t *testing.T) {
+	structured := NewBook(dto.Book{Id: "book-1", Title: "Book One"}, testURL, "DT")
+
+	if structured.Review != nil {
+		t.Errorf("expected no review for an unrated book, got %+v", structured.Review)
+	}
+
+	if structured.DatePublished != "" {
+		t.Errorf("expected no publication date without a year, got %s", structured.DatePublished)
+	}
+}
+
+func TestNewItemList(t *testing.T) {
+	books := []dto.Book{{Id: "book-1", Title: "Book One"}, {Id: "book-2", Title: "Book Two"}}
+
+	itemList := NewItemList("Wishlist", "", books, testURL)
+
+	if itemList.NumberOfItems != 2 || len(itemList.ItemListElement) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(itemList.ItemListElement))
+	}
+
+	second := itemList.ItemListElement[1]
+	if second.Position != 2 || second.URL != "https://example.com/book-2.html" {
+		t.Errorf("expected book-2 at position 2, got %+v", second)
+	}
+}
+
+func TestTemplateEscaping(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`<script type="application/ld+json">{{ . }}</script>`))
+
+	var sb strings.Builder
+	err := tmpl.Execute(&sb, NewBook(dto.Book{Id: "book-1", Title: "</script><script>alert(1)</script>"}, testURL, ""))
+	if err != nil {
+		t.Fatalf("could not execute template: %v", err)
+	}
+
+	if strings.Count(sb.String(), "</script>") != 1 {
+		t.Errorf("expected the title to be escaped within the script element, got %s", sb.String())
+	}
+}
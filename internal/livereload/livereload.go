@@ -0,0 +1,161 @@
+// Package livereload serves a directory with a script injected into its pages
+// which reloads them on server-sent events, e.g. after a rebuild.
+package livereload
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	EventsPath = "/_livereload/events"
+	ScriptPath = "/_livereload/livereload.js"
+
+	// ReloadPage and ReloadCSS are the kinds of reloads, stylesheets are
+	// reloaded without reloading the page
+	ReloadPage = "page"
+	ReloadCSS  = "css"
+)
+
+//go:embed livereload.js
+var script []byte
+
+var scriptTag = []byte(`<script src="` + ScriptPath + `"></script>`)
+
+// Broker sends events to all connected browsers.
+type Broker struct {
+	mu      sync.Mutex
+	clients map[chan event]bool
+}
+
+type event struct {
+	name string
+	data string
+}
+
+func New() *Broker {
+	return &Broker{clients: make(map[chan event]bool)}
+}
+
+// Reload tells all browsers to reload the page or only its stylesheets.
+func (b *Broker) Reload(kind string) {
+	b.send(event{name: "reload", data: kind})
+}
+
+// Error tells all browsers a rebuild failed.
+func (b *Broker) Error(err error) {
+	b.send(event{name: "error", data: strings.ReplaceAll(err.Error(), "\n", " ")})
+}
+
+func (b *Broker) send(e event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for client := range b.clients {
+		// Slow clients miss events instead of blocking the rebuild
+		select {
+		case client <- e:
+		default:
+		}
+	}
+}
+
+func (b *Broker) subscribe() chan event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	client := make(chan event, 8)
+	b.clients[client] = true
+
+	return client
+}
+
+func (b *Broker) unsubscribe(client chan event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, client)
+}
+
+// ServeEvents streams the events to a browser until it disconnects.
+func (b *Broker) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client := b.subscribe()
+	defer b.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-client:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+			flusher.Flush()
+		}
+	}
+}
+
+// Handler serves the files of the root directory with the live reload script
+// injected into all HTML pages, as well as the script and the events.
+func (b *Broker) Handler(root string) http.Handler {
+	files := http.FileServer(http.Dir(root))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EventsPath:
+			b.ServeEvents(w, r)
+			return
+		case ScriptPath:
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			w.Write(script)
+			return
+		}
+
+		name := r.URL.Path
+		if strings.HasSuffix(name, "/") {
+			name += "index.html"
+		}
+
+		if path.Ext(name) == ".html" {
+			content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path.Clean("/"+name))))
+			if err == nil {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Write(Inject(content))
+				return
+			}
+		}
+
+		files.ServeHTTP(w, r)
+	})
+}
+
+// Inject adds the live reload script to the end of the page body.
+func Inject(page []byte) []byte {
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, scriptTag...)
+	}
+
+	injected := make([]byte, 0, len(page)+len(scriptTag))
+	injected = append(injected, page[:i]...)
+	injected = append(injected, scriptTag...)
+
+	return append(injected, page[i:]...)
+}
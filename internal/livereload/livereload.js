@@ -0,0 +1,23 @@
+(function () {
+  const events = new EventSource("/_livereload/events");
+
+  events.addEventListener("reload", function (event) {
+    // Stylesheets are swapped in place to keep the scroll position and state
+    if (event.data === "css") {
+      document.querySelectorAll('link[rel="stylesheet"]').forEach(function (link) {
+        const url = new URL(link.href);
+        url.searchParams.set("livereload", Date.now());
+        link.href = url.href;
+      });
+      return;
+    }
+
+    window.location.reload();
+  });
+
+  events.addEventListener("error", function (event) {
+    if (event.data) {
+      console.error("Build failed: " + event.data);
+    }
+  });
+})();
\ No newline at end of file
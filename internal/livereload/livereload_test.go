@@ -0,0 +1,120 @@
+package livereload
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInject(t *testing.T) {
+	tests := []struct {
+		page     string
+		expected string
+	}{
+		{"<html><body><p>page</p></body></html>", `<html><body><p>page</p><script src="/_livereload/livereload.js"></script></body></html>`},
+		{"<p>fragment</p>", `<p>fragment</p><script src="/_livereload/livereload.js"></script>`},
+	}
+
+	for _, tt := range tests {
+		if injected := string(Inject([]byte(tt.page))); injected != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, injected)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"index.html":    "<html><body>index</body></html>",
+		"css/style.css": "body {}",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("could not write file: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(New().Handler(root))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		injected bool
+	}{
+		{"/", true},
+		{"/index.html", true},
+		{"/css/style.css", false},
+		{ScriptPath, false},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatalf("could not get %s: %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected %s to be served, got status %d", tt.path, resp.StatusCode)
+		}
+		if injected := strings.Contains(string(body), string(scriptTag)); injected != tt.injected {
+			t.Errorf("expected script injected into %s to be %v", tt.path, tt.injected)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	broker := New()
+	server := httptest.NewServer(broker.Handler(t.TempDir()))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+EventsPath, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not connect to events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected an event stream, got %s", contentType)
+	}
+
+	// The client is subscribed once the headers are received
+	broker.Reload(ReloadCSS)
+	broker.Error(errors.New("broken\ntemplate"))
+
+	reader := bufio.NewReader(resp.Body)
+	var received []string
+	for len(received) < 4 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("could not read event: %v", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			received = append(received, line)
+		}
+	}
+
+	expected := []string{"event: reload", "data: css", "event: error", "data: broken template"}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], received[i])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package pages
+
+import (
+	"maps"
+	"slices"
+
+	"bookshelf/internal/api"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+func RenderAPI(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	books := apiBooks(renderer, bookshelf)
+
+	err := writeAPIDocument(renderer, "api/books.json", books)
+	if err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		err = writeAPIDocument(renderer, "api/books/"+book.Id+".json", book)
+		if err != nil {
+			return err
+		}
+	}
+
+	collections := make([]api.Collection, 0, len(bookshelf.Collections))
+	for _, collection := range bookshelf.BookCollections() {
+		collections = append(collections, api.NewCollection(collection, renderer.URL))
+	}
+
+	err = writeAPIDocument(renderer, "api/collections.json", collections)
+	if err != nil {
+		return err
+	}
+
+	err = writeAPIDocument(renderer, "api/stats.json", api.NewStats(bookshelf.Stats()))
+	if err != nil {
+		return err
+	}
+
+	quotes := make([]api.Quote, 0)
+	for _, quote := range bookshelf.BookQuotes() {
+		quotes = append(quotes, api.NewQuote(quote, renderer.URL))
+	}
+
+	return writeAPIDocument(renderer, "api/quotes.json", quotes)
+}
+
+// apiBooks returns all visible books, shelved books in the order of the
+// bookshelf page followed by the wishlist. Books of any other status come last
+// so none of them is left out of the API.
+func apiBooks(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) []api.Book {
+	books := make([]api.Book, 0, len(bookshelf.Books))
+	shelvedBooks := bookshelf.ShelvedBooks()
+
+	for _, status := range shelvedStatusOrder {
+		for _, book := range shelvedBooks[status] {
+			books = append(books, api.NewBook(book, renderer.URL))
+		}
+		delete(shelvedBooks, status)
+	}
+
+	for _, book := range bookshelf.WishlistedBooks() {
+		books = append(books, api.NewBook(book, renderer.URL))
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(shelvedBooks)) {
+		for _, book := range shelvedBooks[status] {
+			books = append(books, api.NewBook(book, renderer.URL))
+		}
+	}
+
+	return books
+}
+
+func writeAPIDocument(renderer *render.TemplateRenderer, fileName string, data any) error {
+	content, err := api.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return renderer.WriteFile(fileName, content)
+}
@@ -0,0 +1,68 @@
+package pages
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+// createLargeBookshelf returns a synthetic bookshelf of n books.
+func createLargeBookshelf(n int) *dto.Bookshelf {
+	statuses := []string{dto.StatusFinished, dto.StatusReading, dto.StatusToRead, dto.StatusWishlisted}
+	bookshelf := &dto.Bookshelf{Books: make([]dto.Book, n)}
+
+	for i := range bookshelf.Books {
+		bookshelf.Books[i] = dto.Book{
+			Id:        fmt.Sprintf("book-%d", i),
+			Isbn:      fmt.Sprintf("978%010d", i),
+			Title:     fmt.Sprintf("Book %d", i),
+			Authors:   []string{fmt.Sprintf("Author %d", i%500)},
+			Year:      1900 + i%125,
+			Language:  "en",
+			Pages:     100 + i%700,
+			Genre:     fmt.Sprintf("genre-%d", i%20),
+			Tags:      []string{"tag", fmt.Sprintf("tag-%d", i%50)},
+			DateAdded: "2025-01-01",
+			Status:    statuses[i%len(statuses)],
+			Progress:  dto.Progress{DateStarted: "2025-02-01", DateFinished: "2025-03-01"},
+			Rating:    float64(i%10) / 2,
+			Review:    []dto.Entry{{Text: "A review of the book."}},
+			Quotes:    []dto.Entry{{Text: "A quote from the book."}},
+		}
+	}
+
+	return bookshelf
+}
+
+func BenchmarkRenderBookPages(b *testing.B) {
+	bookshelf := createLargeBookshelf(10000)
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			renderer, err := render.New(render.TemplateRendererConfig{
+				TemplateType:           "html",
+				TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+				ComponentTemplatesPath: "components",
+				PageTemplatesPath:      "pages",
+				OutputPath:             b.TempDir(),
+				BaseTemplateName:       "base",
+				Site:                   config.Default().Site,
+				Workers:                workers,
+			})
+			if err != nil {
+				b.Fatalf("could not create renderer: %v", err)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				if err := RenderBookPages(renderer, bookshelf); err != nil {
+					b.Fatalf("could not render book pages: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1,18 +1,76 @@
 package pages
 
 import (
+	"fmt"
+	"strings"
+
 	"bookshelf/internal/dto"
+	"bookshelf/internal/jsonld"
 	"bookshelf/internal/render"
 )
 
+const maxDescriptionLength = 160
+
+type bookPageData struct {
+	dto.Book
+
+	structuredData any
+}
+
+func (d bookPageData) Metadata() render.Metadata {
+	description := d.Title
+	if d.Subtitle != "" {
+		description += ": " + d.Subtitle
+	}
+	if len(d.Authors) > 0 {
+		description += " by " + strings.Join(d.Authors, ", ")
+	}
+	if len(d.Review) > 0 {
+		description += ". " + d.Review[0].Text
+	}
+
+	return render.Metadata{
+		Title:          d.Title,
+		Description:    truncate(description, maxDescriptionLength),
+		Image:          d.Cover,
+		Type:           "book",
+		StructuredData: d.structuredData,
+	}
+}
+
 func RenderBookPages(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	jobs := make([]func() error, 0, len(bookshelf.Books))
+
 	for _, book := range bookshelf.Books {
-		err := renderer.RenderToFile("book", book, book.Id)
+		jobs = append(jobs, func() error {
+			data := bookPageData{
+				Book:           book,
+				structuredData: jsonld.NewBook(book, renderer.URL, renderer.Site().Owner),
+			}
+
+			err := renderer.RenderToFile("book", data, book.Id)
+			if err != nil {
+				return fmt.Errorf("book %s: %w", book.Id, err)
+			}
+
+			return nil
+		})
+	}
+
+	return renderer.RenderConcurrently(jobs)
+}
+
+// truncate shortens s to at most n runes, cutting at the last word boundary.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
 
-		if err != nil {
-			return err
-		}
+	truncated := string(r[:n-1])
+	if i := strings.LastIndex(truncated, " "); i > 0 {
+		truncated = truncated[:i]
 	}
 
-	return nil
+	return truncated + "…"
 }
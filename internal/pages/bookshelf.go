@@ -1,18 +1,140 @@
 package pages
 
 import (
+	"encoding/json"
+	"slices"
+
+	"bookshelf/internal/config"
 	"bookshelf/internal/dto"
+	"bookshelf/internal/jsonld"
 	"bookshelf/internal/render"
 )
 
+// shelvedStatusOrder is the order shelved books are listed in.
+var shelvedStatusOrder = []string{dto.StatusReading, dto.StatusToRead, dto.StatusFinished}
+
 type bookshelfPageData struct {
-	Books map[string][]dto.Book
+	Sections       []bookshelfSection
+	Sort           string
+	SortOrders     []string
+	Genres         []string
+	Languages      []string
+	Tags           []string
+	ToReadForecast *dto.Forecast
+
+	structuredData any
+}
+
+type bookshelfSection struct {
+	Status string
+	Books  []bookshelfBook
+}
+
+// bookshelfBook is a book of a section with its positions in the section in
+// all sort orders, so the books can be sorted in the browser.
+type bookshelfBook struct {
+	Book      dto.Book
+	Positions string
+}
+
+func (d bookshelfPageData) Metadata() render.Metadata {
+	return render.Metadata{
+		Title:          "Bookshelf",
+		Description:    "All books I've read, am currently reading or am going to read next.",
+		StructuredData: d.structuredData,
+	}
 }
 
 func RenderBookshelfPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	page := bookshelfPage(renderer.BookshelfPage())
+	shelvedBooks := bookshelf.ShelvedBooks()
+
 	data := bookshelfPageData{
-		Books: bookshelf.ShelvedBooks(),
+		Sort:           page.Sort,
+		SortOrders:     dto.SortOrders,
+		ToReadForecast: bookshelf.ToReadForecast(),
+	}
+
+	var books []dto.Book
+	for _, status := range page.Sections {
+		sectionBooks := shelvedBooks[status]
+
+		positions, err := sortPositions(bookshelf, sectionBooks)
+		if err != nil {
+			return err
+		}
+
+		bookshelf.SortBooks(sectionBooks, page.Sort)
+
+		section := bookshelfSection{Status: status}
+		for _, book := range sectionBooks {
+			section.Books = append(section.Books, bookshelfBook{Book: book, Positions: positions[book.Id]})
+
+			data.Genres = appendNew(data.Genres, book.Genre)
+			data.Languages = appendNew(data.Languages, book.Language)
+			data.Tags = appendNew(data.Tags, book.Tags...)
+		}
+
+		data.Sections = append(data.Sections, section)
+		books = append(books, sectionBooks...)
 	}
 
+	slices.Sort(data.Genres)
+	slices.Sort(data.Languages)
+	slices.Sort(data.Tags)
+	data.structuredData = jsonld.NewItemList("Bookshelf", "", books, renderer.URL)
+
 	return renderer.RenderToFile("bookshelf", data, "bookshelf")
 }
+
+// bookshelfPage returns the configuration of the bookshelf page with defaults
+// for missing values.
+func bookshelfPage(page config.BookshelfPage) config.BookshelfPage {
+	if len(page.Sections) == 0 {
+		page.Sections = shelvedStatusOrder
+	}
+	if page.Sort == "" {
+		page.Sort = dto.SortByTitle
+	}
+
+	return page
+}
+
+// sortPositions returns the positions of the books in all sort orders as JSON
+// by book id, e.g. {"author":2,"title":0,...}.
+func sortPositions(bookshelf *dto.Bookshelf, books []dto.Book) (map[string]string, error) {
+	positions := make(map[string]map[string]int, len(books))
+	for _, book := range books {
+		positions[book.Id] = make(map[string]int, len(dto.SortOrders))
+	}
+
+	sorted := slices.Clone(books)
+	for _, order := range dto.SortOrders {
+		bookshelf.SortBooks(sorted, order)
+		for i, book := range sorted {
+			positions[book.Id][order] = i
+		}
+	}
+
+	encoded := make(map[string]string, len(books))
+	for id, bookPositions := range positions {
+		data, err := json.Marshal(bookPositions)
+		if err != nil {
+			return nil, err
+		}
+		encoded[id] = string(data)
+	}
+
+	return encoded, nil
+}
+
+// appendNew appends the values which are neither empty nor in s yet.
+func appendNew(s []string, values ...string) []string {
+	for _, value := range values {
+		if value != "" && !slices.Contains(s, value) {
+			s = append(s, value)
+		}
+	}
+
+	return s
+}
@@ -2,16 +2,35 @@ package pages
 
 import (
 	"bookshelf/internal/dto"
+	"bookshelf/internal/jsonld"
 	"bookshelf/internal/render"
 )
 
 type collectionsPageData struct {
 	Collections []dto.ResolvedCollection
+
+	structuredData any
+}
+
+func (d collectionsPageData) Metadata() render.Metadata {
+	return render.Metadata{
+		Title:          "Collections",
+		Description:    "Collections of books from my bookshelf.",
+		StructuredData: d.structuredData,
+	}
 }
 
 func RenderCollectionsPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	collections := bookshelf.BookCollections()
+
+	itemLists := make([]jsonld.ItemList, 0, len(collections))
+	for _, collection := range collections {
+		itemLists = append(itemLists, jsonld.NewItemList(collection.Name, collection.Description, collection.Books, renderer.URL))
+	}
+
 	data := collectionsPageData{
-		Collections: bookshelf.BookCollections(),
+		Collections:    collections,
+		structuredData: itemLists,
 	}
 
 	return renderer.RenderToFile("collections", data, "collections")
@@ -0,0 +1,102 @@
+package pages
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"bookshelf/internal/dto"
+	"bookshelf/internal/feed"
+	"bookshelf/internal/render"
+)
+
+const feedEntries = 50
+
+var feedEntryTitles = map[string]string{
+	dto.FeedEntryFinished: "Finished reading",
+	dto.FeedEntryReview:   "Review",
+	dto.FeedEntryAdded:    "Added",
+}
+
+func RenderFeeds(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	f := newFeed(renderer, bookshelf)
+
+	atom, err := feed.Atom(f, renderer.URL("feed.xml"))
+	if err != nil {
+		return err
+	}
+
+	err = renderer.WriteFile("feed.xml", atom)
+	if err != nil {
+		return err
+	}
+
+	rss, err := feed.RSS(f, renderer.URL("rss.xml"))
+	if err != nil {
+		return err
+	}
+
+	err = renderer.WriteFile("rss.xml", rss)
+	if err != nil {
+		return err
+	}
+
+	jsonFeed, err := feed.JSONFeed(f, renderer.URL("feed.json"))
+	if err != nil {
+		return err
+	}
+
+	return renderer.WriteFile("feed.json", jsonFeed)
+}
+
+func newFeed(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) feed.Feed {
+	f := feed.Feed{
+		Title:       renderer.Site().Name(),
+		Description: renderer.Site().Description,
+		Link:        renderer.URL("index.html"),
+	}
+
+	for _, entry := range bookshelf.FeedEntries(feedEntries) {
+		updated, _ := time.Parse("2006-01-02", entry.Date)
+		link := renderer.URL(entry.Book.Id + ".html")
+
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:      fmt.Sprintf("%s#%s-%s", link, entry.Kind, entry.Date),
+			Title:   fmt.Sprintf("%s: %s", feedEntryTitles[entry.Kind], entry.Book.Title),
+			Link:    link,
+			Updated: updated,
+			Summary: feedSummary(entry.Book),
+			Content: feedContent(entry),
+		})
+	}
+
+	return f
+}
+
+func feedSummary(book dto.Book) string {
+	summary := book.Title
+	if len(book.Authors) > 0 {
+		summary += " by " + strings.Join(book.Authors, ", ")
+	}
+
+	return summary
+}
+
+// feedContent returns the review as HTML paragraphs, the feed encoding takes
+// care of escaping the HTML itself.
+func feedContent(entry dto.FeedEntry) string {
+	if entry.Kind != dto.FeedEntryReview {
+		return ""
+	}
+
+	var sb strings.Builder
+	if entry.Book.Rating > 0 {
+		fmt.Fprintf(&sb, "<p>★ %.1f</p>", entry.Book.Rating)
+	}
+	for _, paragraph := range entry.Book.Review {
+		fmt.Fprintf(&sb, "<p>%s</p>", html.EscapeString(paragraph.Text))
+	}
+
+	return sb.String()
+}
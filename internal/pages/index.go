@@ -9,6 +9,7 @@ type indexPageData struct {
 	HasUpcomingBooks bool
 	UpcomingBooks    map[string][]dto.Book
 	Stats            dto.Stats
+	Activity         dto.Activity
 }
 
 func RenderIndexPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
@@ -18,6 +19,7 @@ func RenderIndexPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf
 		HasUpcomingBooks: hasUpcomingBooks,
 		UpcomingBooks:    upcomingBooks,
 		Stats:            bookshelf.Stats(),
+		Activity:         bookshelf.ReadingActivity(26),
 	}
 
 	return renderer.RenderToFile("index", data, "index")
@@ -0,0 +1,382 @@
+package pages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+func createTestBookshelf() *dto.Bookshelf {
+	return &dto.Bookshelf{
+		Books: []dto.Book{
+			{
+				Id: "book-1", Title: "Book One", Authors: []string{"Author A"}, Year: 2001, Language: "en",
+				Pages: 300, Genre: "fiction", DateAdded: "2025-01-01", Status: dto.StatusFinished,
+				Progress: dto.Progress{DateStarted: "2025-01-02", DateFinished: "2025-01-20", PagesRead: 300},
+				Rating:   4.5, Review: []dto.Entry{{Text: "A <great> read."}}, Quotes: []dto.Entry{{Text: "Quote one"}, {Text: "<script>alert(1)</script>"}},
+			},
+			{
+				Id: "book-2", Title: "Book Two", Authors: []string{"Author B"}, Year: 2020, Language: "de",
+				Pages: 200, Genre: "non-fiction", DateAdded: "2025-02-01", Status: dto.StatusReading,
+				Progress: dto.Progress{DateStarted: "2025-11-01", PagesRead: 50},
+			},
+			{Id: "book-3", Title: "Book Three", Authors: []string{"Author A"}, Pages: 150, Genre: "fiction", DateAdded: "2025-03-01", Status: dto.StatusToRead},
+			{Id: "book-4", Title: "Book Four", Authors: []string{"Author C"}, Genre: "fiction", DateAdded: "2025-04-01", Status: dto.StatusWishlisted, Rank: 1},
+		},
+		Collections: []dto.Collection{
+			{Name: "Favorites", Description: "The best ones", Books: []string{"book-1", "book-3"}},
+		},
+	}
+}
+
+// renderSite renders every page, feed and export of the test bookshelf with
+// the given template layers and returns the output path.
+func renderSite(t *testing.T, layers []render.TemplateLayer) string {
+	t.Helper()
+
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         layers,
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   config.Default().Site,
+		Features:               config.Default().Features,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	bookshelf := createTestBookshelf()
+	renderFuncs := map[string]func(*render.TemplateRenderer, *dto.Bookshelf) error{
+		"index":       RenderIndexPage,
+		"bookshelf":   RenderBookshelfPage,
+		"collections": RenderCollectionsPage,
+		"quotes":      RenderQuotesPage,
+		"stats":       RenderStatsPage,
+		"wishlist":    RenderWishlistPage,
+		"books":       RenderBookPages,
+		"feeds":       RenderFeeds,
+		"api":         RenderAPI,
+		"search":      RenderSearchIndex,
+	}
+	for name, renderFunc := range renderFuncs {
+		if err := renderFunc(renderer, bookshelf); err != nil {
+			t.Fatalf("could not render %s: %v", name, err)
+		}
+	}
+
+	if err := RenderSitemap(renderer, bookshelf); err != nil {
+		t.Fatalf("could not render sitemap: %v", err)
+	}
+
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	return outputPath
+}
+
+func readOutput(t *testing.T, outputPath, fileName string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(outputPath, fileName))
+	if err != nil {
+		t.Fatalf("expected %s to be rendered: %v", fileName, err)
+	}
+
+	return string(content)
+}
+
+var expectedPages = []string{
+	"index.html", "bookshelf.html", "collections.html", "quotes.html", "stats.html", "wishlist.html",
+	"book-1.html", "book-2.html", "book-3.html", "book-4.html",
+}
+
+func TestRenderPages(t *testing.T) {
+	outputPath := renderSite(t, []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}})
+
+	for _, page := range expectedPages {
+		content := readOutput(t, outputPath, page)
+
+		if !strings.Contains(content, `class="theme-toggle"`) {
+			t.Errorf("expected %s to be rendered with the default base template", page)
+		}
+	}
+
+	for _, page := range []string{"book-1.html", "quotes.html"} {
+		content := readOutput(t, outputPath, page)
+
+		if strings.Contains(content, "<script>alert(1)</script>") || !strings.Contains(content, "&lt;script&gt;alert(1)&lt;/script&gt;") {
+			t.Errorf("expected the quotes of %s to be escaped", page)
+		}
+	}
+
+	for _, fileName := range []string{"feed.xml", "rss.xml", "feed.json", "api/books.json", "search.json", "sitemap.xml", "robots.txt"} {
+		readOutput(t, outputPath, fileName)
+	}
+}
+
+func TestRenderPages_MinimalTheme(t *testing.T) {
+	outputPath := renderSite(t, []render.TemplateLayer{
+		{Name: "default", FS: os.DirFS("../../templates")},
+		{Name: "theme", FS: os.DirFS("../../themes/minimal")},
+	})
+
+	for _, page := range expectedPages {
+		content := readOutput(t, outputPath, page)
+
+		if strings.Contains(content, `class="theme-toggle"`) {
+			t.Errorf("expected %s to be rendered with the minimal base template", page)
+		}
+		if !strings.Contains(content, `<main class="site-main"`) {
+			t.Errorf("expected %s to contain the page content", page)
+		}
+	}
+
+	if content := readOutput(t, outputPath, "bookshelf.html"); !strings.Contains(content, "book-card-minimal") {
+		t.Error("expected the bookshelf to use the minimal book component")
+	}
+}
+
+func TestRenderShelves(t *testing.T) {
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   config.Default().Site,
+		Features:               config.Default().Features,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	alice := createTestBookshelf()
+	alice.Books[0].Isbn = "978-0-00-000000-2"
+	bob := createTestBookshelf()
+	bob.Books[0].Id = "book-one"
+	bob.Books[0].Isbn = "9780000000002"
+
+	shelves := []dto.Shelf{
+		{Name: "alice", Owner: "Alice", Bookshelf: alice},
+		{Name: "bob", Owner: "Bob", Bookshelf: bob},
+	}
+	if err := RenderShelves(renderer, shelves, config.Default().Features); err != nil {
+		t.Fatalf("could not render shelves: %v", err)
+	}
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	for _, shelf := range []string{"alice", "bob"} {
+		for _, page := range []string{"index.html", "stats.html", "wishlist.html", "feed.xml", "api/books.json"} {
+			readOutput(t, outputPath, filepath.Join(shelf, page))
+		}
+	}
+
+	if content := readOutput(t, outputPath, "alice/bookshelf.html"); !strings.Contains(content, `href="../css/style.css"`) {
+		t.Error("expected the shelf's pages to link the shared static files")
+	}
+
+	if content := readOutput(t, outputPath, "alice/book-1.html"); !strings.Contains(content, `href="../bob/book-one.html"`) {
+		t.Error("expected the book page to link the other reader's book")
+	}
+
+	index := readOutput(t, outputPath, "index.html")
+	for _, expected := range []string{`href="alice/index.html"`, `href="bob/index.html"`, "Book One"} {
+		if !strings.Contains(index, expected) {
+			t.Errorf("expected the landing page to contain %s", expected)
+		}
+	}
+
+	if sitemap := readOutput(t, outputPath, "sitemap.xml"); !strings.Contains(sitemap, "/bob/book-one.html") {
+		t.Error("expected the sitemap to list the pages of all shelves")
+	}
+}
+
+func TestRenderBookshelfPage(t *testing.T) {
+	renderPage := func() string {
+		outputPath := t.TempDir()
+		renderer, err := render.New(render.TemplateRendererConfig{
+			TemplateType:           "html",
+			TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+			ComponentTemplatesPath: "components",
+			PageTemplatesPath:      "pages",
+			OutputPath:             outputPath,
+			BaseTemplateName:       "base",
+			Site:                   config.Default().Site,
+			Features:               config.Default().Features,
+			BookshelfPage:          config.BookshelfPage{Sections: []string{dto.StatusFinished, dto.StatusReading}, Sort: dto.SortByYear},
+		})
+		if err != nil {
+			t.Fatalf("could not create renderer: %v", err)
+		}
+
+		if err := RenderBookshelfPage(renderer, createTestBookshelf()); err != nil {
+			t.Fatalf("could not render bookshelf: %v", err)
+		}
+		if _, err := renderer.Finish(); err != nil {
+			t.Fatalf("could not finish build: %v", err)
+		}
+
+		return readOutput(t, outputPath, "bookshelf.html")
+	}
+
+	content := renderPage()
+
+	finished, reading := strings.Index(content, `id="finished"`), strings.Index(content, `id="reading"`)
+	if finished < 0 || reading < finished {
+		t.Error("expected the sections in the configured order")
+	}
+	if strings.Contains(content, `id="to read"`) {
+		t.Error("expected sections which are not configured to be left out")
+	}
+
+	for _, expected := range []string{
+		`<option value="year" selected>Year</option>`,
+		`<option value="date_finished">Date finished</option>`,
+		`<option value="non-fiction">non-fiction</option>`,
+		`data-positions="{&#34;author&#34;:0,`,
+		`data-rating="4.5"`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected the bookshelf to contain %s", expected)
+		}
+	}
+
+	for range 5 {
+		if renderPage() != content {
+			t.Fatal("expected the bookshelf to be rendered the same every time")
+		}
+	}
+}
+
+func TestRenderPages_StatsDisabled(t *testing.T) {
+	features := config.Default().Features
+	features.Stats = false
+
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   config.Default().Site,
+		Features:               features,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	if err := RenderIndexPage(renderer, createTestBookshelf()); err != nil {
+		t.Fatalf("could not render index: %v", err)
+	}
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	if content := readOutput(t, outputPath, "index.html"); strings.Contains(content, `href="stats.html"`) {
+		t.Error("expected no link to the stats page without the stats feature")
+	}
+}
+
+func TestRenderSitemap_PathPrefix(t *testing.T) {
+	site := config.Default().Site
+	site.BaseURL = "https://example.com"
+	site.PathPrefix = "/bookshelf/"
+
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   site,
+		Features:               config.Default().Features,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	bookshelf := createTestBookshelf()
+	if err := RenderIndexPage(renderer, bookshelf); err != nil {
+		t.Fatalf("could not render index: %v", err)
+	}
+	if err := RenderSitemap(renderer, bookshelf); err != nil {
+		t.Fatalf("could not render sitemap: %v", err)
+	}
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	if content := readOutput(t, outputPath, "sitemap.xml"); !strings.Contains(content, "https://example.com/bookshelf/index.html") {
+		t.Errorf("expected the sitemap to contain the prefixed URL, got %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, "robots.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no robots.txt below a path prefix, got %v", err)
+	}
+}
+
+func TestRenderAPI_AllStatuses(t *testing.T) {
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   config.Default().Site,
+		Features:               config.Default().Features,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	bookshelf := createTestBookshelf()
+	bookshelf.Books = append(bookshelf.Books, dto.Book{Id: "book-5", Title: "Book Five", DateAdded: "2025-05-01", Status: "abandoned"})
+
+	if err := RenderAPI(renderer, bookshelf); err != nil {
+		t.Fatalf("could not render API: %v", err)
+	}
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	var document struct {
+		Data []struct {
+			Id string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, outputPath, "api/books.json")), &document); err != nil {
+		t.Fatalf("could not parse api/books.json: %v", err)
+	}
+
+	var ids []string
+	for _, book := range document.Data {
+		ids = append(ids, book.Id)
+	}
+
+	expected := []string{"book-2", "book-3", "book-1", "book-4", "book-5"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("expected books %v, got %v", expected, ids)
+	}
+	readOutput(t, outputPath, "api/books/book-5.json")
+}
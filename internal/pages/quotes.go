@@ -9,6 +9,13 @@ type quotesPageData struct {
 	Quotes []dto.Quote
 }
 
+func (d quotesPageData) Metadata() render.Metadata {
+	return render.Metadata{
+		Title:       "Quotes",
+		Description: "My favorite quotes from all the books I've read.",
+	}
+}
+
 func RenderQuotesPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
 	data := quotesPageData{
 		Quotes: bookshelf.BookQuotes(),
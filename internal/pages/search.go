@@ -0,0 +1,19 @@
+package pages
+
+import (
+	"encoding/json"
+
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+	"bookshelf/internal/search"
+)
+
+// RenderSearchIndex writes the search.json searched by the search box.
+func RenderSearchIndex(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	content, err := json.Marshal(search.New(bookshelf.Books))
+	if err != nil {
+		return err
+	}
+
+	return renderer.WriteFile("search.json", content)
+}
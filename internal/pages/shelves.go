@@ -0,0 +1,132 @@
+package pages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+type shelvesPageData struct {
+	Members []memberData
+	Shared  []dto.SharedBook
+}
+
+type memberData struct {
+	Shelf   dto.Shelf
+	Stats   dto.Stats
+	Reading []dto.Book
+}
+
+// RenderShelves renders a site with a shelf per member: every shelf is a site
+// of its own in the directory of its name, the index page at the root compares
+// the members.
+func RenderShelves(renderer *render.TemplateRenderer, shelves []dto.Shelf, features config.Features) error {
+	dto.LinkSharedBooks(shelves)
+
+	// The sitemap of the site lists the pages of all shelves
+	shelfFeatures := features
+	shelfFeatures.Sitemap = false
+
+	lastUpdated := ""
+	for _, shelf := range shelves {
+		lastModified := shelf.Bookshelf.LastModified()
+		lastUpdated = max(lastUpdated, lastModified)
+
+		shelfRenderer, err := renderer.Subsite(render.SubsiteConfig{
+			Dir:         shelf.Name,
+			Site:        shelfSite(renderer.Site(), shelf),
+			Features:    shelfFeatures,
+			LastUpdated: lastModified,
+		})
+		if err != nil {
+			return fmt.Errorf("shelf %s: %w", shelf.Name, err)
+		}
+
+		err = RenderSite(shelfRenderer, shelf.Bookshelf, shelfFeatures)
+		if err != nil {
+			return fmt.Errorf("shelf %s: %w", shelf.Name, err)
+		}
+	}
+
+	// There are no feeds or API documents at the root of the site
+	landingRenderer, err := renderer.Subsite(render.SubsiteConfig{
+		Site:        landingSite(renderer.Site(), shelves),
+		Features:    config.Features{Stats: features.Stats},
+		LastUpdated: lastUpdated,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = RenderShelvesPage(landingRenderer, shelves)
+	if err != nil {
+		return fmt.Errorf("rendering shelves page: %w", err)
+	}
+
+	if !features.Sitemap {
+		return nil
+	}
+
+	err = renderShelvesSitemap(renderer, shelves, lastUpdated)
+	if err != nil {
+		return fmt.Errorf("rendering sitemap: %w", err)
+	}
+
+	return nil
+}
+
+func RenderShelvesPage(renderer *render.TemplateRenderer, shelves []dto.Shelf) error {
+	data := shelvesPageData{Shared: dto.SharedBooks(shelves)}
+
+	for _, shelf := range shelves {
+		reading := shelf.Bookshelf.ShelvedBooks()[dto.StatusReading]
+
+		data.Members = append(data.Members, memberData{
+			Shelf:   dto.Shelf{Name: shelf.Name, Owner: shelf.Owner},
+			Stats:   shelf.Bookshelf.Stats(),
+			Reading: reading,
+		})
+	}
+
+	return renderer.RenderToFile("shelves", data, "index")
+}
+
+// shelfSite returns the site of a member's shelf, named after the member and
+// linking back to the landing page.
+func shelfSite(site config.Site, shelf dto.Shelf) config.Site {
+	site.Owner = shelf.Owner
+	if site.Initials != "" {
+		site.Initials = initials(shelf.Owner)
+	}
+	site.Nav = append(slices.Clone(site.Nav), config.NavEntry{Label: "Everyone", Href: "../index.html"})
+
+	return site
+}
+
+// landingSite returns the site of the landing page, linking all shelves.
+func landingSite(site config.Site, shelves []dto.Shelf) config.Site {
+	site.Nav = make([]config.NavEntry, 0, len(shelves))
+	for _, shelf := range shelves {
+		site.Nav = append(site.Nav, config.NavEntry{Label: shelf.Owner, Href: shelf.Name + "/index.html"})
+	}
+
+	return site
+}
+
+// initials returns the first letters of up to two words of the name.
+func initials(name string) string {
+	var letters []rune
+	for _, word := range strings.Fields(name) {
+		letters = append(letters, unicode.ToUpper([]rune(word)[0]))
+		if len(letters) == 2 {
+			break
+		}
+	}
+
+	return string(letters)
+}
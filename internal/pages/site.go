@@ -0,0 +1,46 @@
+package pages
+
+import (
+	"fmt"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+type renderStep struct {
+	name    string
+	enabled bool
+	render  func(*render.TemplateRenderer, *dto.Bookshelf) error
+}
+
+// RenderSite renders all pages, feeds and exports enabled by the features.
+func RenderSite(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf, features config.Features) error {
+	steps := []renderStep{
+		{"index page", true, RenderIndexPage},
+		{"bookshelf page", true, RenderBookshelfPage},
+		{"collections page", true, RenderCollectionsPage},
+		{"quotes page", true, RenderQuotesPage},
+		{"stats page", features.Stats, RenderStatsPage},
+		{"wishlist page", true, RenderWishlistPage},
+		{"book pages", true, RenderBookPages},
+		{"feeds", features.Feeds, RenderFeeds},
+		{"JSON API", features.API, RenderAPI},
+		{"search index", features.Search, RenderSearchIndex},
+		// The sitemap lists all outputs and has to be rendered last
+		{"sitemap", features.Sitemap, RenderSitemap},
+	}
+
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+
+		err := step.render(renderer, bookshelf)
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
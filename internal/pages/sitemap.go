@@ -0,0 +1,85 @@
+package pages
+
+import (
+	"log"
+	"path"
+	"strings"
+
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+	"bookshelf/internal/sitemap"
+)
+
+// RenderSitemap writes the sitemap.xml of all pages rendered so far and the
+// robots.txt, so it has to be called after all other pages are rendered.
+//
+// Crawlers only read the robots.txt at the root of the host, so it is left out
+// when the site is published below a path prefix.
+func RenderSitemap(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	bookById := make(map[string]dto.Book, len(bookshelf.Books))
+	for _, book := range bookshelf.Books {
+		bookById[book.Id+".html"] = book
+	}
+
+	lastModified := bookshelf.LastModified()
+
+	return writeSitemap(renderer, func(output string) string {
+		if book, ok := bookById[output]; ok {
+			return bookshelf.BookLastModified(book)
+		}
+		return lastModified
+	})
+}
+
+// renderShelvesSitemap writes the sitemap of a site with several shelves, the
+// pages of a shelf are dated by its books.
+func renderShelvesSitemap(renderer *render.TemplateRenderer, shelves []dto.Shelf, lastUpdated string) error {
+	shelfByName := make(map[string]dto.Shelf, len(shelves))
+	for _, shelf := range shelves {
+		shelfByName[shelf.Name] = shelf
+	}
+
+	return writeSitemap(renderer, func(output string) string {
+		name, page, ok := strings.Cut(output, "/")
+		shelf, found := shelfByName[name]
+		if !ok || !found {
+			return lastUpdated
+		}
+
+		if book, ok := shelf.Bookshelf.Book(strings.TrimSuffix(page, ".html")); ok {
+			return shelf.Bookshelf.BookLastModified(book)
+		}
+		return shelf.Bookshelf.LastModified()
+	})
+}
+
+// writeSitemap writes the sitemap.xml of all pages rendered so far dated by
+// lastModified, and the robots.txt if the site is published at the root of its
+// host.
+func writeSitemap(renderer *render.TemplateRenderer, lastModified func(output string) string) error {
+	var urls []sitemap.URL
+	for _, output := range renderer.Outputs() {
+		if path.Ext(output) != ".html" {
+			continue
+		}
+
+		urls = append(urls, sitemap.URL{Loc: renderer.URL(output), LastMod: lastModified(output)})
+	}
+
+	content, err := sitemap.Encode(urls)
+	if err != nil {
+		return err
+	}
+
+	err = renderer.WriteFile("sitemap.xml", content)
+	if err != nil {
+		return err
+	}
+
+	if root := renderer.Path(""); root != "/" {
+		log.Printf("Not writing robots.txt, the site is published at %s and crawlers only read robots.txt at the root of the host. Point them to %s in the robots.txt there", root, renderer.URL("sitemap.xml"))
+		return nil
+	}
+
+	return renderer.WriteFile("robots.txt", sitemap.Robots(renderer.Path(""), renderer.URL("sitemap.xml")))
+}
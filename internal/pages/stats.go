@@ -0,0 +1,33 @@
+package pages
+
+import (
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+type statsPageData struct {
+	Stats           dto.Stats
+	StatsByYear     []dto.FilteredStats
+	StatsByGenre    []dto.FilteredStats
+	StatsByLanguage []dto.FilteredStats
+	Records         dto.Records
+}
+
+func (d statsPageData) Metadata() render.Metadata {
+	return render.Metadata{
+		Title:       "Stats",
+		Description: "My bookshelf by the numbers, the most read authors and other reading records.",
+	}
+}
+
+func RenderStatsPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	data := statsPageData{
+		Stats:           bookshelf.Stats(),
+		StatsByYear:     bookshelf.StatsByYear(),
+		StatsByGenre:    bookshelf.StatsByGenre(),
+		StatsByLanguage: bookshelf.StatsByLanguage(),
+		Records:         bookshelf.Records(2),
+	}
+
+	return renderer.RenderToFile("stats", data, "stats")
+}
@@ -2,16 +2,30 @@ package pages
 
 import (
 	"bookshelf/internal/dto"
+	"bookshelf/internal/jsonld"
 	"bookshelf/internal/render"
 )
 
 type wishlistPageData struct {
 	Books []dto.Book
+
+	structuredData any
+}
+
+func (d wishlistPageData) Metadata() render.Metadata {
+	return render.Metadata{
+		Title:          "Wishlist",
+		Description:    "Books on my wishlist, ordered by my personal reading priority.",
+		StructuredData: d.structuredData,
+	}
 }
 
 func RenderWishlistPage(renderer *render.TemplateRenderer, bookshelf *dto.Bookshelf) error {
+	books := bookshelf.WishlistedBooks()
+
 	data := wishlistPageData{
-		Books: bookshelf.WishlistedBooks(),
+		Books:          books,
+		structuredData: jsonld.NewItemList("Wishlist", "", books, renderer.URL),
 	}
 
 	return renderer.RenderToFile("wishlist", data, "wishlist")
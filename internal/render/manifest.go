@@ -0,0 +1,157 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ManifestFileName is the file in the output path listing the files written by
+// the last build and the hashes of their content.
+const ManifestFileName = ".manifest.json"
+
+type manifest struct {
+	Files map[string]string `json:"files"`
+}
+
+type output struct {
+	hash    string
+	written bool
+}
+
+// BuildSummary counts the files written, left unchanged and removed by a build.
+type BuildSummary struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
+
+func (s BuildSummary) String() string {
+	return fmt.Sprintf("%d written, %d unchanged, %d removed", s.Written, s.Unchanged, s.Removed)
+}
+
+func loadManifest(outputPath string) (manifest, error) {
+	data, err := os.ReadFile(filepath.Join(outputPath, ManifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest{}, nil
+	}
+	if err != nil {
+		return manifest{}, fmt.Errorf("reading build manifest: %w", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return manifest{}, fmt.Errorf("unmarshal build manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+func contentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+type outputAction int
+
+const (
+	// outputStaged files were already written by this build with the same content
+	outputStaged outputAction = iota
+	// outputUnchanged files are linked from the last build into the staging directory
+	outputUnchanged
+	outputWrite
+)
+
+// recordOutput adds the file to the outputs of this build and returns how it
+// gets into the staging directory.
+func (r *TemplateRenderer) recordOutput(fileName, hash string) outputAction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.outputs == nil {
+		r.outputs = make(map[string]output)
+	}
+
+	staged, ok := r.outputs[fileName]
+	switch {
+	case ok && staged.hash == hash:
+		return outputStaged
+	case ok:
+		r.outputs[fileName] = output{hash: hash, written: true}
+		return outputWrite
+	case r.previous.Files[fileName] == hash:
+		r.outputs[fileName] = output{hash: hash}
+		return outputUnchanged
+	default:
+		r.outputs[fileName] = output{hash: hash, written: true}
+		return outputWrite
+	}
+}
+
+// markWritten records that an unchanged file had to be written, e.g. because it
+// was missing from the output of the last build.
+func (r *TemplateRenderer) markWritten(fileName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	output := r.outputs[fileName]
+	output.written = true
+	r.outputs[fileName] = output
+}
+
+// Finish saves the manifest of this build and replaces the output path with
+// the staging directory, so the output only contains the files of this build.
+// The renderer can not be used afterwards.
+func (r *TemplateRenderer) Finish() (BuildSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var summary BuildSummary
+	current := manifest{Files: make(map[string]string, len(r.outputs))}
+	for fileName, output := range r.outputs {
+		current.Files[fileName] = output.hash
+
+		if output.written {
+			summary.Written++
+		} else {
+			summary.Unchanged++
+		}
+	}
+
+	// Files of the last build not written by this one, e.g. pages of deleted
+	// books, are not part of the staging directory
+	for fileName := range r.previous.Files {
+		if _, ok := current.Files[fileName]; !ok {
+			summary.Removed++
+		}
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return summary, err
+	}
+
+	err = os.WriteFile(filepath.Join(r.stagingPath, ManifestFileName), data, 0o644)
+	if err != nil {
+		return summary, err
+	}
+
+	err = swap(r.stagingPath, r.config.OutputPath)
+	if err != nil {
+		return summary, err
+	}
+	r.previous = current
+
+	return summary, nil
+}
+
+// Abort discards the staging directory of a failed build, leaving the output
+// of the last build in place.
+func (r *TemplateRenderer) Abort() error {
+	return os.RemoveAll(r.stagingPath)
+}
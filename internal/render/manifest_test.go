@@ -0,0 +1,203 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestRenderer(t *testing.T, outputPath string) *TemplateRenderer {
+	t.Helper()
+
+	templates := fstest.MapFS{"base.html": {Data: []byte(`{{ define "base" }}{{ end }}`)}}
+	renderer, err := New(TemplateRendererConfig{
+		TemplateType:     "html",
+		TemplateLayers:   []TemplateLayer{{Name: "default", FS: templates}},
+		OutputPath:       outputPath,
+		BaseTemplateName: "base",
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	return renderer
+}
+
+func build(t *testing.T, outputPath string, files map[string]string) BuildSummary {
+	t.Helper()
+
+	renderer := newTestRenderer(t, outputPath)
+	for fileName, content := range files {
+		if err := renderer.WriteFile(fileName, []byte(content)); err != nil {
+			t.Fatalf("could not write %s: %v", fileName, err)
+		}
+	}
+
+	summary, err := renderer.Finish()
+	if err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	return summary
+}
+
+func TestIncrementalBuild(t *testing.T) {
+	outputPath := t.TempDir()
+
+	summary := build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "one", "api/books/book-2.json": "two"})
+	if expected := (BuildSummary{Written: 3}); summary != expected {
+		t.Errorf("expected first build %v, got %v", expected, summary)
+	}
+
+	// The output only contains the files of the last build
+	if err := os.WriteFile(filepath.Join(outputPath, "orphan.html"), []byte("orphan"), 0o644); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+
+	summary = build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "changed"})
+	if expected := (BuildSummary{Written: 1, Unchanged: 1, Removed: 1}); summary != expected {
+		t.Errorf("expected second build %v, got %v", expected, summary)
+	}
+
+	expected := map[string]string{"index.html": "index", "book-1.html": "changed"}
+	for fileName, content := range expected {
+		data, err := os.ReadFile(filepath.Join(outputPath, fileName))
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", fileName, err)
+		}
+		if string(data) != content {
+			t.Errorf("expected %s to contain %q, got %q", fileName, content, data)
+		}
+	}
+
+	for _, fileName := range []string{"api/books/book-2.json", "orphan.html"} {
+		if _, err := os.Stat(filepath.Join(outputPath, fileName)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", fileName, err)
+		}
+	}
+
+	// Files missing from the output are written again even if unchanged
+	if err := os.Remove(filepath.Join(outputPath, "index.html")); err != nil {
+		t.Fatalf("could not remove file: %v", err)
+	}
+
+	summary = build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "changed"})
+	if expected := (BuildSummary{Written: 1, Unchanged: 1}); summary != expected {
+		t.Errorf("expected third build %v, got %v", expected, summary)
+	}
+}
+
+func TestIncrementalBuild_SameFileTwice(t *testing.T) {
+	outputPath := t.TempDir()
+	build(t, outputPath, map[string]string{"style.css": "embedded"})
+
+	renderer := newTestRenderer(t, outputPath)
+	for _, content := range []string{"disk", "embedded"} {
+		if err := renderer.WriteFile("style.css", []byte(content)); err != nil {
+			t.Fatalf("could not write file: %v", err)
+		}
+	}
+
+	summary, err := renderer.Finish()
+	if err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+	if expected := (BuildSummary{Written: 1}); summary != expected {
+		t.Errorf("expected %v, got %v", expected, summary)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputPath, "style.css"))
+	if err != nil || string(data) != "embedded" {
+		t.Errorf("expected the last content to be written, got %q (%v)", data, err)
+	}
+}
+
+func TestIncrementalBuild_LinkedFileNotModified(t *testing.T) {
+	outputPath := t.TempDir()
+	build(t, outputPath, map[string]string{"index.html": "index"})
+
+	// The unchanged file is linked from the output, writing it again must not
+	// change the output before the build is finished
+	renderer := newTestRenderer(t, outputPath)
+	for _, content := range []string{"index", "changed"} {
+		if err := renderer.WriteFile("index.html", []byte(content)); err != nil {
+			t.Fatalf("could not write file: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputPath, "index.html"))
+	if err != nil || string(data) != "index" {
+		t.Errorf("expected the output to be unchanged until the build is finished, got %q (%v)", data, err)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	outputPath := t.TempDir()
+	build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "one"})
+
+	renderer := newTestRenderer(t, outputPath)
+	if err := renderer.WriteFile("index.html", []byte("half-written")); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+	if err := renderer.Abort(); err != nil {
+		t.Fatalf("could not abort build: %v", err)
+	}
+
+	for fileName, content := range map[string]string{"index.html": "index", "book-1.html": "one"} {
+		data, err := os.ReadFile(filepath.Join(outputPath, fileName))
+		if err != nil || string(data) != content {
+			t.Errorf("expected %s of the last build to be kept, got %q (%v)", fileName, data, err)
+		}
+	}
+
+	if _, err := os.Stat(stagingPath(outputPath)); !os.IsNotExist(err) {
+		t.Errorf("expected the staging directory to be removed, got %v", err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "dist")
+	build(t, outputPath, map[string]string{"index.html": "index"})
+
+	// Leftovers of an interrupted build
+	if err := os.MkdirAll(stagingPath(outputPath), os.ModePerm); err != nil {
+		t.Fatalf("could not create staging directory: %v", err)
+	}
+
+	if err := Clean(outputPath); err != nil {
+		t.Fatalf("could not clean output: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(outputPath))
+	if err != nil {
+		t.Fatalf("could not read directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected output and staging directory to be removed, got %v", entries)
+	}
+
+	summary := build(t, outputPath, map[string]string{"index.html": "index"})
+	if expected := (BuildSummary{Written: 1}); summary != expected {
+		t.Errorf("expected a build from scratch %v, got %v", expected, summary)
+	}
+}
+
+func TestRestorePrevious(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "dist")
+	build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "one"})
+
+	// A build stopped between the renames of swap
+	if err := os.Rename(outputPath, previousPath(outputPath)); err != nil {
+		t.Fatalf("could not move output aside: %v", err)
+	}
+
+	summary := build(t, outputPath, map[string]string{"index.html": "index", "book-1.html": "one"})
+	if expected := (BuildSummary{Unchanged: 2}); summary != expected {
+		t.Errorf("expected the output of the last build to be restored %v, got %v", expected, summary)
+	}
+
+	if _, err := os.Stat(previousPath(outputPath)); !os.IsNotExist(err) {
+		t.Errorf("expected the previous output to be moved back, got %v", err)
+	}
+}
@@ -1,36 +1,120 @@
 package render
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"html/template"
-	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
+
+	"bookshelf/internal/chart"
+	"bookshelf/internal/config"
 )
 
 type TemplateRenderer struct {
 	config       TemplateRendererConfig
 	baseTemplate *template.Template
+
+	// pageTemplates caches the base template combined with each page template
+	templatesMu   sync.Mutex
+	pageTemplates map[string]*template.Template
+
+	// outputs is the manifest of all files written by this build and previous
+	// the manifest of the last build, see Finish
+	mu       sync.Mutex
+	outputs  map[string]output
+	previous manifest
+
+	// stagingPath is the directory files are written to until the build is
+	// finished, files holds the files of an in-memory renderer instead
+	stagingPath string
+	files       map[string][]byte
+
+	// parent is the renderer all files of a subsite are written through, dir
+	// the directory of the subsite relative to the parent's output path
+	parent *TemplateRenderer
+	dir    string
 }
 
 type TemplateRendererConfig struct {
 	TemplateType           string
-	TemplatesPath          string
+	TemplateLayers         []TemplateLayer
 	ComponentTemplatesPath string
 	PageTemplatesPath      string
 	OutputPath             string
 	BaseTemplateName       string
+	Site                   config.Site
+	Features               config.Features
+	BookshelfPage          config.BookshelfPage
+
+	// LastUpdated is the date shown as last update of the site, defaults to
+	// the date of the build
+	LastUpdated string
+
+	// InMemory keeps all files in memory instead of writing them to the output
+	// path, see Files
+	InMemory bool
+
+	// Workers is the number of pages rendered concurrently, defaults to the
+	// number of CPUs
+	Workers int
+}
+
+// TemplateLayer is a file system of templates, e.g. the embedded defaults or a
+// directory on disk. Any base, component or page template in a layer overrides
+// the template of the same file name in all layers before it.
+type TemplateLayer struct {
+	Name string
+	FS   fs.FS
+}
+
+// Metadata describes a page for search engines and link previews.
+type Metadata struct {
+	Title       string
+	Description string
+	Image       string
+	Type        string
+
+	// StructuredData is embedded as JSON-LD, see package jsonld
+	StructuredData any
+}
+
+// MetadataProvider is implemented by page data describing its own page, pages
+// without metadata are described by the site title and description.
+type MetadataProvider interface {
+	Metadata() Metadata
 }
 
 type templateData struct {
 	Page        any
+	Meta        pageMeta
 	LastUpdated string
 }
 
+type pageMeta struct {
+	Title          string
+	Description    string
+	CanonicalURL   string
+	Image          string
+	Type           string
+	SiteTitle      string
+	StructuredData any
+}
+
 var funcMap = template.FuncMap{
 	"join": strings.Join,
+	"replace": func(s, old, new string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
 	"title": func(s string) string {
 		if s == "" {
 			return ""
@@ -42,85 +126,465 @@ var funcMap = template.FuncMap{
 	"safeHTML": func(s string) template.HTML {
 		return template.HTML(strings.ReplaceAll(s, "\n", "<br>"))
 	},
+	"heatmap":    chart.Heatmap,
+	"barChart":   chart.Bar,
+	"donutChart": chart.Donut,
+	"histogram":  chart.Histogram,
 }
 
 func New(config TemplateRendererConfig) (*TemplateRenderer, error) {
-	baseTemplate, err := template.New("").Funcs(funcMap).ParseFiles(filepath.Join(config.TemplatesPath, config.BaseTemplateName+"."+config.TemplateType))
+	for _, layer := range config.TemplateLayers {
+		if _, err := fs.Stat(layer.FS, "."); err != nil {
+			return nil, fmt.Errorf("%s templates: %w", layer.Name, err)
+		}
+	}
+
+	r := &TemplateRenderer{config: config}
+	if !config.InMemory {
+		if err := restorePrevious(config.OutputPath); err != nil {
+			return nil, fmt.Errorf("restoring output of the last build: %w", err)
+		}
+
+		previous, err := loadManifest(config.OutputPath)
+		if err != nil {
+			return nil, err
+		}
+		r.previous = previous
+	}
+
+	err := r.parseBaseTemplate()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = baseTemplate.ParseGlob(filepath.Join(config.TemplatesPath, config.ComponentTemplatesPath, "*."+config.TemplateType))
+	if config.InMemory {
+		r.files = make(map[string][]byte)
+		return r, nil
+	}
+
+	r.stagingPath, err = createStagingDir(config.OutputPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TemplateRenderer{config: config, baseTemplate: baseTemplate}, nil
+	return r, nil
 }
 
-func (r *TemplateRenderer) RenderToFile(templateName string, data any, outputName string) error {
-	pageTemplate, err := r.baseTemplate.Clone()
+// SubsiteConfig configures a part of a site rendered into a directory with its
+// own site configuration, e.g. the shelf of one member of a household.
+type SubsiteConfig struct {
+	// Dir is the directory relative to the output path, empty for pages at
+	// the root of the site
+	Dir         string
+	Site        config.Site
+	Features    config.Features
+	LastUpdated string
+}
+
+// Subsite returns a renderer for the pages of a subsite. All files are written
+// through r and are part of its build, so the subsite is finished with r.
+func (r *TemplateRenderer) Subsite(subsite SubsiteConfig) (*TemplateRenderer, error) {
+	config := r.config
+	config.Site = subsite.Site
+	config.Features = subsite.Features
+	config.LastUpdated = subsite.LastUpdated
+
+	s := &TemplateRenderer{config: config, parent: r.root(), dir: path.Join(r.dir, subsite.Dir)}
+	if s.dir == "." {
+		s.dir = ""
+	}
+
+	err := s.parseBaseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// root returns the renderer writing the files of the site.
+func (r *TemplateRenderer) root() *TemplateRenderer {
+	if r.parent != nil {
+		return r.parent
+	}
+
+	return r
+}
+
+// parseBaseTemplate parses the base template and all component templates.
+func (r *TemplateRenderer) parseBaseTemplate() error {
+	baseTemplate := template.New("").Funcs(funcMap).Funcs(r.funcMap())
+
+	err := r.parseTemplate(baseTemplate, r.config.BaseTemplateName+"."+r.config.TemplateType)
 	if err != nil {
 		return err
 	}
 
-	templatePath := filepath.Join(
-		r.config.TemplatesPath,
-		r.config.PageTemplatesPath,
-		templateName+"."+r.config.TemplateType,
-	)
-	_, err = pageTemplate.ParseFiles(templatePath)
+	components, err := r.componentTemplates()
 	if err != nil {
 		return err
 	}
 
-	outputFileName := outputName + ".html"
-	outputPath := filepath.Join(r.config.OutputPath, outputFileName)
+	for _, component := range components {
+		err = r.parseTemplate(baseTemplate, path.Join(r.config.ComponentTemplatesPath, component))
+		if err != nil {
+			return err
+		}
+	}
+
+	r.baseTemplate = baseTemplate
+
+	return nil
+}
 
-	file, err := os.Create(outputPath)
+// funcMap exposes the site configuration to all templates, including the page
+// content which only receives the page data. root is the relative path from
+// the pages to the root of the site, e.g. "../" in a subsite, to link static
+// files.
+func (r *TemplateRenderer) funcMap() template.FuncMap {
+	root := ""
+	if r.dir != "" {
+		root = strings.Repeat("../", strings.Count(r.dir, "/")+1)
+	}
+
+	return template.FuncMap{
+		"site":     r.Site,
+		"features": func() config.Features { return r.config.Features },
+		"root":     func() string { return root },
+	}
+}
+
+// RenderToFile renders the page template with the data into the output file,
+// it is safe for concurrent use.
+func (r *TemplateRenderer) RenderToFile(templateName string, data any, outputName string) error {
+	pageTemplate, err := r.pageTemplate(templateName)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+
+	outputFileName := outputName + ".html"
+
+	lastUpdated := r.config.LastUpdated
+	if lastUpdated == "" {
+		lastUpdated = time.Now().Format("2006-01-02")
+	}
 
 	templateData := templateData{
 		Page:        data,
-		LastUpdated: time.Now().Format("2006-01-02"),
+		Meta:        r.pageMeta(data, outputFileName),
+		LastUpdated: lastUpdated,
 	}
 
-	return pageTemplate.ExecuteTemplate(file, r.config.BaseTemplateName, templateData)
+	var buf bytes.Buffer
+	err = pageTemplate.ExecuteTemplate(&buf, r.config.BaseTemplateName, templateData)
+	if err != nil {
+		return err
+	}
+
+	return r.WriteFile(outputFileName, buf.Bytes())
 }
 
-func (r *TemplateRenderer) CopyStaticFiles(srcDir, dstDir string) error {
-	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// RenderConcurrently runs the render jobs on a bounded number of workers. All
+// jobs are run, the errors of failed jobs are joined in the order of the jobs.
+func (r *TemplateRenderer) RenderConcurrently(jobs []func() error) error {
+	workers := r.config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	queue := make(chan int)
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				errs[i] = jobs[i]()
+			}
+		}()
+	}
+
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// pageTemplate returns the base template combined with the page template,
+// parsing it on first use.
+func (r *TemplateRenderer) pageTemplate(templateName string) (*template.Template, error) {
+	r.templatesMu.Lock()
+	defer r.templatesMu.Unlock()
+
+	if pageTemplate, ok := r.pageTemplates[templateName]; ok {
+		return pageTemplate, nil
+	}
+
+	pageTemplate, err := r.baseTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.parseTemplate(pageTemplate, path.Join(r.config.PageTemplatesPath, templateName+"."+r.config.TemplateType))
+	if err != nil {
+		return nil, err
+	}
+
+	if r.pageTemplates == nil {
+		r.pageTemplates = make(map[string]*template.Template)
+	}
+	r.pageTemplates[templateName] = pageTemplate
+
+	return pageTemplate, nil
+}
+
+// parseTemplate parses the template file from the last layer containing it,
+// errors name the layer the template came from.
+func (r *TemplateRenderer) parseTemplate(t *template.Template, name string) error {
+	layer, ok := r.lookupTemplate(name)
+	if !ok {
+		return fmt.Errorf("template %s not found in any layer", name)
+	}
+
+	_, err := t.ParseFS(layer.FS, name)
+	if err != nil {
+		return fmt.Errorf("%s template %s: %w", layer.Name, name, err)
+	}
+
+	return nil
+}
+
+func (r *TemplateRenderer) lookupTemplate(name string) (TemplateLayer, bool) {
+	for i := len(r.config.TemplateLayers) - 1; i >= 0; i-- {
+		layer := r.config.TemplateLayers[i]
+
+		if info, err := fs.Stat(layer.FS, name); err == nil && !info.IsDir() {
+			return layer, true
 		}
+	}
 
-		relPath, err := filepath.Rel(srcDir, path)
+	return TemplateLayer{}, false
+}
+
+// componentTemplates returns the file names of the component templates of all
+// layers in alphabetical order.
+func (r *TemplateRenderer) componentTemplates() ([]string, error) {
+	seen := make(map[string]bool)
+	var components []string
+
+	for _, layer := range r.config.TemplateLayers {
+		paths, err := fs.Glob(layer.FS, path.Join(r.config.ComponentTemplatesPath, "*."+r.config.TemplateType))
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("%s templates: %w", layer.Name, err)
 		}
 
-		destPath := filepath.Join(dstDir, relPath)
+		for _, componentPath := range paths {
+			name := path.Base(componentPath)
+			if !seen[name] {
+				seen[name] = true
+				components = append(components, name)
+			}
+		}
+	}
+	sort.Strings(components)
 
-		if info.IsDir() {
-			return os.MkdirAll(destPath, os.ModePerm)
+	return components, nil
+}
+
+func (r *TemplateRenderer) pageMeta(data any, outputFileName string) pageMeta {
+	var metadata Metadata
+	if provider, ok := data.(MetadataProvider); ok {
+		metadata = provider.Metadata()
+	}
+
+	siteTitle := r.config.Site.Name()
+	meta := pageMeta{
+		Title:          siteTitle,
+		Description:    r.config.Site.Description,
+		CanonicalURL:   r.URL(outputFileName),
+		Image:          metadata.Image,
+		Type:           metadata.Type,
+		SiteTitle:      siteTitle,
+		StructuredData: metadata.StructuredData,
+	}
+
+	if metadata.Title != "" {
+		meta.Title = metadata.Title + " - " + siteTitle
+	}
+	if metadata.Description != "" {
+		meta.Description = metadata.Description
+	}
+	if meta.Image == "" && r.config.Site.Image != "" {
+		// The image is a static file at the root of the site
+		meta.Image = r.root().URL(r.config.Site.Image)
+	}
+	if meta.Type == "" {
+		meta.Type = "website"
+	}
+
+	return meta
+}
+
+// WriteFile writes content that is not rendered from a template, e.g. feeds,
+// to the given file name relative to the output path. Files whose content did
+// not change since the last build are not rewritten.
+func (r *TemplateRenderer) WriteFile(fileName string, content []byte) error {
+	fileName = filepath.ToSlash(fileName)
+	if r.parent != nil {
+		return r.parent.WriteFile(path.Join(r.dir, fileName), content)
+	}
+
+	if r.config.InMemory {
+		r.storeFile(fileName, content)
+		return nil
+	}
+
+	stagedPath := filepath.Join(r.stagingPath, filepath.FromSlash(fileName))
+
+	action := r.recordOutput(fileName, contentHash(content))
+	if action == outputStaged {
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(stagedPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	// A file staged before may be linked to the output of the last build,
+	// which must not be modified
+	err = os.Remove(stagedPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if action == outputUnchanged {
+		// Linking keeps the file and its timestamps as they were
+		err = os.Link(filepath.Join(r.config.OutputPath, filepath.FromSlash(fileName)), stagedPath)
+		if err == nil {
+			return nil
+		}
+		r.markWritten(fileName)
+	}
+
+	return os.WriteFile(stagedPath, content, 0o644)
+}
+
+func (r *TemplateRenderer) storeFile(fileName string, content []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.outputs == nil {
+		r.outputs = make(map[string]output)
+	}
+	r.outputs[fileName] = output{hash: contentHash(content), written: true}
+	r.files[fileName] = bytes.Clone(content)
+}
+
+// Files returns the content of all files written so far by an in-memory
+// renderer, by file name relative to the output path of the site.
+func (r *TemplateRenderer) Files() map[string][]byte {
+	r = r.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files := make(map[string][]byte, len(r.files))
+	for fileName, content := range r.files {
+		files[fileName] = content
+	}
+
+	return files
+}
+
+// Outputs returns the file names of all pages and files written so far,
+// relative to the output path of the site and in alphabetical order.
+func (r *TemplateRenderer) Outputs() []string {
+	r = r.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	outputs := make([]string, 0, len(r.outputs))
+	for output := range r.outputs {
+		outputs = append(outputs, output)
+	}
+	sort.Strings(outputs)
+
+	return outputs
+}
+
+// Site returns the configuration of the site being rendered, its navigation
+// without links to pages of disabled features.
+func (r *TemplateRenderer) Site() config.Site {
+	site := r.config.Site
+	site.Nav = site.EnabledNav(r.config.Features)
+
+	return site
+}
+
+// BookshelfPage returns the configuration of the bookshelf page.
+func (r *TemplateRenderer) BookshelfPage() config.BookshelfPage {
+	return r.config.BookshelfPage
+}
+
+// URL returns the absolute URL of a path relative to the site's base URL and
+// path prefix.
+func (r *TemplateRenderer) URL(path string) string {
+	return strings.TrimRight(r.config.Site.BaseURL, "/") + r.Path(path)
+}
+
+// Path returns the absolute path of a path relative to the site's path prefix,
+// paths of a subsite are relative to its directory.
+func (r *TemplateRenderer) Path(path string) string {
+	prefix := strings.Trim(r.config.Site.PathPrefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	if r.dir != "" {
+		prefix += "/" + r.dir
+	}
+
+	return prefix + "/" + strings.TrimLeft(path, "/")
+}
+
+// CopyStaticFiles writes all files of the static file systems to the output
+// path, files in later file systems replace files of the same name in earlier
+// ones.
+func (r *TemplateRenderer) CopyStaticFiles(static ...fs.FS) error {
+	files := make(map[string]fs.FS)
+	for _, fsys := range static {
+		err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !entry.IsDir() {
+				files[path] = fsys
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		srcFile, err := os.Open(path)
+	for path, fsys := range files {
+		content, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return err
 		}
-		defer srcFile.Close()
 
-		dstFile, err := os.Create(destPath)
+		err = r.WriteFile(path, content)
 		if err != nil {
 			return err
 		}
-		defer dstFile.Close()
+	}
 
-		_, err = io.Copy(dstFile, srcFile)
-		return err
-	})
+	return nil
 }
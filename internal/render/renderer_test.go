@@ -0,0 +1,332 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"bookshelf/internal/config"
+)
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		baseURL    string
+		pathPrefix string
+		path       string
+		expected   string
+	}{
+		{"https://example.com", "/bookshelf", "index.html", "https://example.com/bookshelf/index.html"},
+		{"https://example.com/", "bookshelf/", "/feed.xml", "https://example.com/bookshelf/feed.xml"},
+		{"https://example.com", "", "sitemap.xml", "https://example.com/sitemap.xml"},
+		{"https://example.com", "/bookshelf", "", "https://example.com/bookshelf/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			renderer := &TemplateRenderer{config: TemplateRendererConfig{Site: config.Site{BaseURL: tt.baseURL, PathPrefix: tt.pathPrefix}}}
+
+			if url := renderer.URL(tt.path); url != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, url)
+			}
+		})
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	outputPath := t.TempDir()
+	renderer := newTestRenderer(t, outputPath)
+
+	for _, fileName := range []string{"rss.xml", "api/books.json", "rss.xml"} {
+		if err := renderer.WriteFile(fileName, []byte("content")); err != nil {
+			t.Fatalf("could not write %s: %v", fileName, err)
+		}
+	}
+
+	expected := []string{"api/books.json", "rss.xml"}
+	if outputs := renderer.Outputs(); !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("expected outputs %v, got %v", expected, outputs)
+	}
+
+	finish(t, renderer)
+
+	if _, err := os.Stat(filepath.Join(outputPath, "api", "books.json")); err != nil {
+		t.Errorf("expected file in sub directory to be written: %v", err)
+	}
+}
+
+type testPage struct{}
+
+func (p testPage) Metadata() Metadata {
+	return Metadata{Title: "Book One", Description: "Book One by Author A", Image: "https://example.com/cover.jpg", Type: "book"}
+}
+
+func TestPageMeta(t *testing.T) {
+	renderer := &TemplateRenderer{config: TemplateRendererConfig{Site: config.Site{
+		BaseURL:     "https://example.com",
+		PathPrefix:  "/bookshelf",
+		Title:       "My Bookshelf",
+		Description: "All my books",
+		Image:       "icons/icon.png",
+	}}}
+
+	meta := renderer.pageMeta(testPage{}, "book-1.html")
+
+	expected := pageMeta{
+		Title:        "Book One - My Bookshelf",
+		Description:  "Book One by Author A",
+		CanonicalURL: "https://example.com/bookshelf/book-1.html",
+		Image:        "https://example.com/cover.jpg",
+		Type:         "book",
+		SiteTitle:    "My Bookshelf",
+	}
+	if meta != expected {
+		t.Errorf("expected %+v, got %+v", expected, meta)
+	}
+
+	meta = renderer.pageMeta(struct{}{}, "index.html")
+
+	expected = pageMeta{
+		Title:        "My Bookshelf",
+		Description:  "All my books",
+		CanonicalURL: "https://example.com/bookshelf/index.html",
+		Image:        "https://example.com/bookshelf/icons/icon.png",
+		Type:         "website",
+		SiteTitle:    "My Bookshelf",
+	}
+	if meta != expected {
+		t.Errorf("expected %+v, got %+v", expected, meta)
+	}
+}
+
+func finish(t *testing.T, renderer *TemplateRenderer) {
+	t.Helper()
+
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+}
+
+func newLayeredRenderer(t *testing.T, layers ...TemplateLayer) (*TemplateRenderer, error) {
+	t.Helper()
+
+	return New(TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         layers,
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             t.TempDir(),
+		BaseTemplateName:       "base",
+	})
+}
+
+func TestTemplateLayers(t *testing.T) {
+	defaults := fstest.MapFS{
+		"base.html":            {Data: []byte(`{{ define "base" }}<main>{{ template "content" .Page }}</main>{{ end }}`)},
+		"components/book.html": {Data: []byte(`{{ define "book" }}default book{{ end }}`)},
+		"components/card.html": {Data: []byte(`{{ define "card" }}default card{{ end }}`)},
+		"pages/index.html":     {Data: []byte(`{{ define "content" }}{{ template "book" }} {{ template "card" }}{{ end }}`)},
+	}
+	theme := fstest.MapFS{
+		"components/book.html": {Data: []byte(`{{ define "book" }}theme book{{ end }}`)},
+		"components/card.html": {Data: []byte(`{{ define "card" }}theme card{{ end }}`)},
+	}
+	overrides := fstest.MapFS{
+		"base.html":            {Data: []byte(`{{ define "base" }}<body>{{ template "content" .Page }}</body>{{ end }}`)},
+		"components/card.html": {Data: []byte(`{{ define "card" }}override card{{ end }}`)},
+	}
+
+	renderer, err := newLayeredRenderer(t,
+		TemplateLayer{Name: "default", FS: defaults},
+		TemplateLayer{Name: "theme", FS: theme},
+		TemplateLayer{Name: "overrides", FS: overrides},
+	)
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	if err := renderer.RenderToFile("index", nil, "index"); err != nil {
+		t.Fatalf("could not render page: %v", err)
+	}
+	finish(t, renderer)
+
+	content, err := os.ReadFile(filepath.Join(renderer.config.OutputPath, "index.html"))
+	if err != nil {
+		t.Fatalf("could not read rendered page: %v", err)
+	}
+
+	expected := "<body>theme book override card</body>"
+	if string(content) != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+}
+
+func TestTemplateLayers_Errors(t *testing.T) {
+	defaults := fstest.MapFS{
+		"base.html":        {Data: []byte(`{{ define "base" }}{{ template "content" .Page }}{{ end }}`)},
+		"pages/index.html": {Data: []byte(`{{ define "content" }}index{{ end }}`)},
+	}
+	broken := fstest.MapFS{
+		"components/book.html": {Data: []byte(`{{ define "book" }}{{ if }}{{ end }}`)},
+	}
+
+	_, err := newLayeredRenderer(t, TemplateLayer{Name: "default", FS: defaults}, TemplateLayer{Name: "overrides", FS: broken})
+	if err == nil || !strings.Contains(err.Error(), "overrides template components/book.html") {
+		t.Errorf("expected error naming the overrides layer, got %v", err)
+	}
+
+	_, err = newLayeredRenderer(t, TemplateLayer{Name: "theme", FS: os.DirFS(filepath.Join(t.TempDir(), "missing"))})
+	if err == nil || !strings.Contains(err.Error(), "theme templates") {
+		t.Errorf("expected error naming the missing theme layer, got %v", err)
+	}
+
+	renderer, err := newLayeredRenderer(t, TemplateLayer{Name: "default", FS: defaults})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	err = renderer.RenderToFile("stats", nil, "stats")
+	if err == nil || !strings.Contains(err.Error(), "not found in any layer") {
+		t.Errorf("expected error for a missing page template, got %v", err)
+	}
+}
+
+func TestCopyStaticFiles(t *testing.T) {
+	dstDir := t.TempDir()
+	renderer := newTestRenderer(t, dstDir)
+
+	embedded := fstest.MapFS{
+		"css/style.css": {Data: []byte("embedded")},
+		"js/app.js":     {Data: []byte("embedded")},
+	}
+	disk := fstest.MapFS{
+		"css/style.css": {Data: []byte("disk")},
+	}
+
+	if err := renderer.CopyStaticFiles(embedded, disk); err != nil {
+		t.Fatalf("could not copy static files: %v", err)
+	}
+	finish(t, renderer)
+
+	expected := map[string]string{"css/style.css": "disk", "js/app.js": "embedded"}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(dstDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("expected %s to be copied: %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("expected %s to contain %q, got %q", name, content, data)
+		}
+	}
+}
+
+func TestRenderConcurrently(t *testing.T) {
+	renderer := &TemplateRenderer{config: TemplateRendererConfig{Workers: 2}}
+
+	var running, maxRunning, completed atomic.Int32
+	jobs := make([]func() error, 10)
+	for i := range jobs {
+		jobs[i] = func() error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			completed.Add(1)
+
+			if i%4 == 1 {
+				return fmt.Errorf("job %d failed", i)
+			}
+			return nil
+		}
+	}
+
+	err := renderer.RenderConcurrently(jobs)
+
+	if completed.Load() != 10 {
+		t.Errorf("expected all 10 jobs to run despite errors, got %d", completed.Load())
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("expected at most 2 jobs to run at once, got %d", maxRunning.Load())
+	}
+
+	expected := "job 1 failed\njob 5 failed\njob 9 failed"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected errors %q, got %v", expected, err)
+	}
+}
+
+func TestPageTemplateCache(t *testing.T) {
+	templates := fstest.MapFS{
+		"base.html":        {Data: []byte(`{{ define "base" }}{{ template "content" .Page }}{{ end }}`)},
+		"pages/index.html": {Data: []byte(`{{ define "content" }}first{{ end }}`)},
+	}
+
+	renderer, err := newLayeredRenderer(t, TemplateLayer{Name: "default", FS: templates})
+	if err != nil {
+		t.Fatalf("could not create renderer: %v", err)
+	}
+
+	for _, outputName := range []string{"first", "second"} {
+		if err := renderer.RenderToFile("index", nil, outputName); err != nil {
+			t.Fatalf("could not render page: %v", err)
+		}
+
+		// Changes after the first render are not picked up, the page is parsed once
+		templates["pages/index.html"] = &fstest.MapFile{Data: []byte(`{{ define "content" }}second{{ end }}`)}
+	}
+	finish(t, renderer)
+
+	content, err := os.ReadFile(filepath.Join(renderer.config.OutputPath, "second.html"))
+	if err != nil {
+		t.Fatalf("could not read rendered page: %v", err)
+	}
+	if string(content) != "first" {
+		t.Errorf("expected the cached page template to be used, got %q", content)
+	}
+}
+
+func TestSubsite(t *testing.T) {
+	outputPath := t.TempDir()
+	renderer := newTestRenderer(t, outputPath)
+
+	subsite, err := renderer.Subsite(SubsiteConfig{
+		Dir:  "alice",
+		Site: config.Site{Title: "Alice's Shelf", BaseURL: "https://example.com"},
+	})
+	if err != nil {
+		t.Fatalf("could not create subsite: %v", err)
+	}
+
+	if err := subsite.WriteFile("index.html", []byte("alice")); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+	if err := renderer.WriteFile("index.html", []byte("everyone")); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+
+	if url := subsite.URL("index.html"); url != "https://example.com/alice/index.html" {
+		t.Errorf("expected the url of the subsite's page, got %s", url)
+	}
+
+	expected := []string{"alice/index.html", "index.html"}
+	if outputs := renderer.Outputs(); !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("expected outputs %v, got %v", expected, outputs)
+	}
+
+	finish(t, renderer)
+
+	if content, err := os.ReadFile(filepath.Join(outputPath, "alice", "index.html")); err != nil || string(content) != "alice" {
+		t.Errorf("expected the subsite's file to be written into its directory, got %q: %v", content, err)
+	}
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stagingPath and previousPath are hidden directories next to the output path,
+// so they are on the same file system and moving them is a rename.
+func stagingPath(outputPath string) string {
+	return siblingPath(outputPath, "staging")
+}
+
+func previousPath(outputPath string) string {
+	return siblingPath(outputPath, "previous")
+}
+
+func siblingPath(outputPath, suffix string) string {
+	outputPath = filepath.Clean(outputPath)
+	return filepath.Join(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+"."+suffix)
+}
+
+// createStagingDir creates an empty staging directory for the output path,
+// replacing the leftovers of an interrupted build.
+func createStagingDir(outputPath string) (string, error) {
+	staging := stagingPath(outputPath)
+
+	err := os.RemoveAll(staging)
+	if err != nil {
+		return "", err
+	}
+
+	return staging, os.MkdirAll(staging, os.ModePerm)
+}
+
+// swap moves the staging directory into place of the output path. These are
+// two renames, so the output path is missing for a moment in between: the
+// output of the last build is moved aside first. It is moved back if the
+// second rename fails, or by restorePrevious on the next build if the process
+// stops in between.
+func swap(staging, outputPath string) error {
+	previous := previousPath(outputPath)
+
+	err := os.RemoveAll(previous)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(outputPath, previous)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	err = os.Rename(staging, outputPath)
+	if err != nil {
+		if restoreErr := os.Rename(previous, outputPath); restoreErr != nil && !errors.Is(restoreErr, fs.ErrNotExist) {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+
+	return os.RemoveAll(previous)
+}
+
+// restorePrevious moves the output of the last build back into place if a
+// build stopped in the middle of swap.
+func restorePrevious(outputPath string) error {
+	_, err := os.Stat(outputPath)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	err = os.Rename(previousPath(outputPath), outputPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Clean removes the output path including the build manifest, as well as the
+// staging directory of an interrupted build, so the next build starts from
+// scratch.
+func Clean(outputPath string) error {
+	for _, path := range []string{outputPath, stagingPath(outputPath), previousPath(outputPath)} {
+		err := os.RemoveAll(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
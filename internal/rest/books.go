@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"bookshelf/internal/dto"
+)
+
+type quoteRequest struct {
+	Quote      string `json:"quote"`
+	Visibility string `json:"visibility"`
+}
+
+func (h *Handler) listBooks(w http.ResponseWriter, r *http.Request) {
+	books := h.config.Store.Bookshelf().Books
+	if books == nil {
+		books = []dto.Book{}
+	}
+
+	writeDocument(w, http.StatusOK, books)
+}
+
+func (h *Handler) createBook(w http.ResponseWriter, r *http.Request) {
+	var book dto.Book
+	if err := decode(w, r, &book); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var created dto.Book
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		var err error
+		created, err = bookshelf.AddBook(book)
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Location", "/api/books/"+created.Id)
+	writeDocument(w, http.StatusCreated, created)
+}
+
+func (h *Handler) getBook(w http.ResponseWriter, r *http.Request) {
+	book, ok := h.config.Store.Bookshelf().Book(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, dto.ErrBookNotFound)
+		return
+	}
+
+	writeDocument(w, http.StatusOK, book)
+}
+
+// patchBook applies a JSON merge patch to the book, e.g. {"rating": 4.5}.
+func (h *Handler) patchBook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	patch, err := decodePatch(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var patched dto.Book
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		book, ok := bookshelf.Book(id)
+		if !ok {
+			return dto.ErrBookNotFound
+		}
+
+		err := checkPrecondition(r, book, true)
+		if err != nil {
+			return err
+		}
+
+		err = applyPatch(book, patch, &patched)
+		if err != nil {
+			return err
+		}
+		if patched.Id != id {
+			return &dto.ValidationError{Errors: []dto.FieldError{{Field: "id", Message: "can not be changed"}}}
+		}
+
+		return bookshelf.UpdateBook(id, patched)
+	})
+	if !ok {
+		return
+	}
+
+	writeDocument(w, http.StatusOK, patched)
+}
+
+func (h *Handler) deleteBook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		book, ok := bookshelf.Book(id)
+		if !ok {
+			return dto.ErrBookNotFound
+		}
+
+		err := checkPrecondition(r, book, true)
+		if err != nil {
+			return err
+		}
+
+		return bookshelf.DeleteBook(id)
+	})
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listQuotes(w http.ResponseWriter, r *http.Request) {
+	book, ok := h.config.Store.Bookshelf().Book(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, dto.ErrBookNotFound)
+		return
+	}
+
+	writeDocument(w, http.StatusOK, quotes(book))
+}
+
+// createQuote adds a quote to the book, the If-Match header is optional as
+// adding a quote does not overwrite other changes.
+func (h *Handler) createQuote(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var request quoteRequest
+	if err := decode(w, r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var book dto.Book
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		current, ok := bookshelf.Book(id)
+		if !ok {
+			return dto.ErrBookNotFound
+		}
+
+		err := checkPrecondition(r, quotes(current), false)
+		if err != nil {
+			return err
+		}
+
+		err = bookshelf.AddQuote(id, dto.Entry{Text: request.Quote, Visibility: request.Visibility})
+		if err != nil {
+			return err
+		}
+
+		book, _ = bookshelf.Book(id)
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Location", "/api/books/"+id+"/quotes/"+strconv.Itoa(len(book.Quotes)-1))
+	writeDocument(w, http.StatusCreated, quotes(book))
+}
+
+// deleteQuote removes the quote at the index, the If-Match header has to
+// match the quotes of the book, so the index refers to the expected quote.
+func (h *Handler) deleteQuote(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, dto.ErrQuoteNotFound)
+		return
+	}
+
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		book, ok := bookshelf.Book(id)
+		if !ok {
+			return dto.ErrBookNotFound
+		}
+
+		err := checkPrecondition(r, quotes(book), true)
+		if err != nil {
+			return err
+		}
+
+		return bookshelf.DeleteQuote(id, index)
+	})
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// quotes returns the quotes of the book, an empty list if it has none.
+func quotes(book dto.Book) []dto.Entry {
+	if book.Quotes == nil {
+		return []dto.Entry{}
+	}
+
+	return book.Quotes
+}
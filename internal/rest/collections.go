@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+
+	"bookshelf/internal/dto"
+)
+
+func (h *Handler) listCollections(w http.ResponseWriter, r *http.Request) {
+	collections := h.config.Store.Bookshelf().Collections
+	if collections == nil {
+		collections = []dto.Collection{}
+	}
+
+	writeDocument(w, http.StatusOK, collections)
+}
+
+func (h *Handler) createCollection(w http.ResponseWriter, r *http.Request) {
+	var collection dto.Collection
+	if err := decode(w, r, &collection); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		return bookshelf.AddCollection(collection)
+	})
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Location", "/api/collections/"+url.PathEscape(collection.Name))
+	writeDocument(w, http.StatusCreated, collection)
+}
+
+func (h *Handler) getCollection(w http.ResponseWriter, r *http.Request) {
+	collection, ok := h.config.Store.Bookshelf().Collection(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, dto.ErrCollectionNotFound)
+		return
+	}
+
+	writeDocument(w, http.StatusOK, collection)
+}
+
+// patchCollection applies a JSON merge patch to the collection, it is renamed
+// if the patch changes its name.
+func (h *Handler) patchCollection(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	patch, err := decodePatch(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var patched dto.Collection
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		collection, ok := bookshelf.Collection(name)
+		if !ok {
+			return dto.ErrCollectionNotFound
+		}
+
+		err := checkPrecondition(r, collection, true)
+		if err != nil {
+			return err
+		}
+
+		err = applyPatch(collection, patch, &patched)
+		if err != nil {
+			return err
+		}
+
+		return bookshelf.UpdateCollection(name, patched)
+	})
+	if !ok {
+		return
+	}
+
+	if patched.Name != name {
+		w.Header().Set("Location", "/api/collections/"+url.PathEscape(patched.Name))
+	}
+	writeDocument(w, http.StatusOK, patched)
+}
+
+func (h *Handler) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	ok := h.update(w, func(bookshelf *dto.Bookshelf) error {
+		collection, ok := bookshelf.Collection(name)
+		if !ok {
+			return dto.ErrCollectionNotFound
+		}
+
+		err := checkPrecondition(r, collection, true)
+		if err != nil {
+			return err
+		}
+
+		return bookshelf.DeleteCollection(name)
+	})
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,318 @@
+// Package rest serves a JSON REST API in server mode to read and change the
+// bookshelf from scripts, authenticated with tokens. Documents are wrapped
+// into the envelope of the static JSON API.
+package rest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"bookshelf/internal/api"
+	"bookshelf/internal/dto"
+)
+
+// maxBodySize limits the size of request bodies.
+const maxBodySize = 1 << 20
+
+type Config struct {
+	// TokenHashes are the hashes of the accepted tokens created by
+	// GenerateToken
+	TokenHashes []string
+	Store       dto.Store
+
+	// Static serves the documents of the static JSON API, e.g.
+	// api/books/{id}.json, which share their paths with the REST API
+	Static http.Handler
+}
+
+type Handler struct {
+	config Config
+	mux    *http.ServeMux
+}
+
+// Error is the document of an error response, validation errors list the
+// invalid fields.
+type Error struct {
+	Status  int              `json:"status"`
+	Message string           `json:"message"`
+	Fields  []dto.FieldError `json:"fields,omitempty"`
+}
+
+type errorDocument struct {
+	Error Error `json:"error"`
+}
+
+var (
+	errPreconditionFailed   = errors.New("the resource was changed, fetch it again to get its current ETag")
+	errPreconditionRequired = errors.New("the If-Match header with the ETag of the resource is required")
+)
+
+// Patterns are the routes of the REST API, so they can be registered next to
+// other handlers.
+var Patterns = []string{
+	"/api/books",
+	"/api/books/{id}",
+	"/api/books/{id}/quotes",
+	"/api/books/{id}/quotes/{index}",
+	"/api/collections",
+	"/api/collections/{name}",
+}
+
+func New(config Config) *Handler {
+	h := &Handler{config: config, mux: http.NewServeMux()}
+
+	h.mux.HandleFunc("GET /api/books", h.listBooks)
+	h.mux.HandleFunc("POST /api/books", h.createBook)
+	h.mux.HandleFunc("GET /api/books/{id}", h.getBook)
+	h.mux.HandleFunc("PATCH /api/books/{id}", h.patchBook)
+	h.mux.HandleFunc("DELETE /api/books/{id}", h.deleteBook)
+	h.mux.HandleFunc("GET /api/books/{id}/quotes", h.listQuotes)
+	h.mux.HandleFunc("POST /api/books/{id}/quotes", h.createQuote)
+	h.mux.HandleFunc("DELETE /api/books/{id}/quotes/{index}", h.deleteQuote)
+
+	h.mux.HandleFunc("GET /api/collections", h.listCollections)
+	h.mux.HandleFunc("POST /api/collections", h.createCollection)
+	h.mux.HandleFunc("GET /api/collections/{name}", h.getCollection)
+	h.mux.HandleFunc("PATCH /api/collections/{name}", h.patchCollection)
+	h.mux.HandleFunc("DELETE /api/collections/{name}", h.deleteCollection)
+
+	return h
+}
+
+// GenerateToken returns a random token and the hash of it for the
+// configuration.
+func GenerateToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Book ids never contain dots, so these are documents of the static API
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && strings.HasSuffix(r.URL.Path, ".json") && h.config.Static != nil {
+		h.config.Static.ServeHTTP(w, r)
+		return
+	}
+
+	if !h.authenticated(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="bookshelf"`)
+		writeError(w, http.StatusUnauthorized, errors.New("a valid token is required in the Authorization header"))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.mux.ServeHTTP(w, r)
+}
+
+// authenticated reports whether the request carries one of the tokens as
+// bearer token.
+func (h *Handler) authenticated(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+
+	hash := []byte(hashToken(token))
+	authenticated := false
+	for _, tokenHash := range h.config.TokenHashes {
+		if subtle.ConstantTimeCompare(hash, []byte(strings.ToLower(tokenHash))) == 1 {
+			authenticated = true
+		}
+	}
+
+	return authenticated
+}
+
+// update applies the changes and writes the errors of the update as response.
+// It reports whether the update succeeded.
+func (h *Handler) update(w http.ResponseWriter, update func(bookshelf *dto.Bookshelf) error) bool {
+	err := h.config.Store.Update(update)
+
+	var validationErr *dto.ValidationError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusUnprocessableEntity, "", errorDocument{Error: Error{
+			Status:  http.StatusUnprocessableEntity,
+			Message: "invalid fields",
+			Fields:  validationErr.Errors,
+		}})
+	case errors.Is(err, dto.ErrBookNotFound), errors.Is(err, dto.ErrCollectionNotFound), errors.Is(err, dto.ErrQuoteNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, errPreconditionFailed):
+		writeError(w, http.StatusPreconditionFailed, err)
+	case errors.Is(err, errPreconditionRequired):
+		writeError(w, http.StatusPreconditionRequired, err)
+	case errors.Is(err, errInvalidBody):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		log.Printf("Failed to handle API request: %v", err)
+		writeError(w, http.StatusInternalServerError, errors.New(http.StatusText(http.StatusInternalServerError)))
+	}
+
+	return false
+}
+
+var errInvalidBody = errors.New("invalid request body")
+
+// decode reads the JSON body, fields unknown to the target are rejected.
+func decode(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidBody, err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("%w: more than one JSON value", errInvalidBody)
+	}
+
+	return nil
+}
+
+// decodePatch reads a JSON merge patch (RFC 7396) from the body.
+func decodePatch(w http.ResponseWriter, r *http.Request) (map[string]any, error) {
+	var patch map[string]any
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the merge patch must be a JSON object: %w", errInvalidBody, err)
+	}
+
+	return patch, nil
+}
+
+// applyPatch applies the merge patch to the JSON representation of the
+// document and decodes the result into v.
+func applyPatch(document any, patch map[string]any, v any) error {
+	content, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	var target any
+	err = json.Unmarshal(content, &target)
+	if err != nil {
+		return err
+	}
+
+	content, err = json.Marshal(merge(target, patch))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidBody, err)
+	}
+
+	return nil
+}
+
+func merge(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = merge(targetObject[key], value)
+		}
+	}
+
+	return targetObject
+}
+
+// etag returns the entity tag of a resource, it changes with every change of
+// the resource.
+func etag(v any) string {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(content)
+
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// checkPrecondition compares the If-Match header with the current ETag of the
+// resource, so changes based on an outdated resource are rejected.
+func checkPrecondition(r *http.Request, current any, required bool) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if required {
+			return errPreconditionRequired
+		}
+		return nil
+	}
+
+	currentETag := etag(current)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == currentETag {
+			return nil
+		}
+	}
+
+	return errPreconditionFailed
+}
+
+// writeDocument writes the data in the envelope of the static JSON API with
+// the ETag of the data.
+func writeDocument(w http.ResponseWriter, status int, data any) {
+	writeJSON(w, status, etag(data), api.Document{Version: api.Version, Data: data})
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, "", errorDocument{Error: Error{Status: status, Message: err.Error()}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, etag string, v any) {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal API response: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.WriteHeader(status)
+	w.Write(append(content, '\n'))
+}
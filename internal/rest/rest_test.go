@@ -0,0 +1,282 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bookshelf/internal/dto"
+	"bookshelf/internal/storetest"
+)
+
+const testToken = "test-token"
+
+type response struct {
+	*httptest.ResponseRecorder
+}
+
+// data decodes the data of the response document into v.
+func (r response) data(t *testing.T, v any) {
+	t.Helper()
+
+	document := struct {
+		Version int             `json:"version"`
+		Data    json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(r.Body.Bytes(), &document); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if document.Version != 1 {
+		t.Errorf("expected document with version 1, got %d", document.Version)
+	}
+	if err := json.Unmarshal(document.Data, v); err != nil {
+		t.Fatalf("could not decode data: %v", err)
+	}
+}
+
+func (r response) error(t *testing.T) Error {
+	t.Helper()
+
+	var document errorDocument
+	if err := json.Unmarshal(r.Body.Bytes(), &document); err != nil {
+		t.Fatalf("could not decode error: %v", err)
+	}
+
+	return document.Error
+}
+
+func newTestHandler(t *testing.T) (*Handler, *storetest.Store) {
+	t.Helper()
+
+	store := storetest.New(&dto.Bookshelf{
+		Books: []dto.Book{
+			{Id: "book-1", Title: "Book One", Pages: 300, Status: dto.StatusReading, Quotes: []dto.Entry{{Text: "First."}, {Text: "Second."}}},
+			{Id: "book-2", Title: "Book Two", Status: dto.StatusToRead},
+		},
+		Collections: []dto.Collection{{Name: "Favorites", Books: []string{"book-1"}}},
+	})
+
+	static := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("static " + r.URL.Path))
+	})
+
+	return New(Config{TokenHashes: []string{hashToken(testToken)}, Store: store, Static: static}), store
+}
+
+func request(h http.Handler, method, target, body string, header map[string]string) response {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return response{rec}
+}
+
+func TestAuthentication(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	for _, authorization := range []string{"", "Bearer ", "Bearer wrong", testToken} {
+		resp := request(h, http.MethodGet, "/api/books", "", map[string]string{"Authorization": authorization})
+		if resp.Code != http.StatusUnauthorized || resp.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("expected authorization %q to be rejected, got status %d", authorization, resp.Code)
+		}
+	}
+
+	if resp := request(h, http.MethodGet, "/api/books", "", nil); resp.Code != http.StatusOK {
+		t.Errorf("expected valid token to be accepted, got status %d", resp.Code)
+	}
+
+	// Documents of the static API are served without a token
+	resp := request(h, http.MethodGet, "/api/books/book-1.json", "", map[string]string{"Authorization": ""})
+	if resp.Code != http.StatusOK || resp.Body.String() != "static /api/books/book-1.json" {
+		t.Errorf("expected static document, got status %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = request(h, http.MethodHead, "/api/books/book-1.json", "", map[string]string{"Authorization": ""})
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected HEAD of a static document without a token, got status %d", resp.Code)
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	token, hash, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("could not generate token: %v", err)
+	}
+
+	h := New(Config{TokenHashes: []string{hash}, Store: storetest.New(&dto.Bookshelf{})})
+	resp := request(h, http.MethodGet, "/api/books", "", map[string]string{"Authorization": "Bearer " + token})
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected generated token to be accepted, got status %d", resp.Code)
+	}
+}
+
+func TestBooks(t *testing.T) {
+	h, store := newTestHandler(t)
+
+	var books []dto.Book
+	request(h, http.MethodGet, "/api/books", "", nil).data(t, &books)
+	if len(books) != 2 {
+		t.Errorf("expected 2 books, got %d", len(books))
+	}
+
+	resp := request(h, http.MethodPost, "/api/books", `{"title": "Book Three", "status": "to read", "pages": 200}`, nil)
+	if resp.Code != http.StatusCreated || resp.Header().Get("Location") != "/api/books/book-three" {
+		t.Fatalf("expected book to be created, got status %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = request(h, http.MethodGet, "/api/books/book-three", "", nil)
+	var book dto.Book
+	resp.data(t, &book)
+	if book.Title != "Book Three" || book.Pages != 200 {
+		t.Errorf("expected created book, got %+v", book)
+	}
+	etag := resp.Header().Get("ETag")
+
+	resp = request(h, http.MethodPatch, "/api/books/book-three", `{"status": "reading", "progress": {"pages_read": 20}}`, map[string]string{"If-Match": etag})
+	if resp.Code != http.StatusOK || resp.Header().Get("ETag") == etag {
+		t.Fatalf("expected book to be patched with a new ETag, got status %d: %s", resp.Code, resp.Body.String())
+	}
+	if book, _ := store.Bookshelf().Book("book-three"); book.Status != dto.StatusReading || book.Progress.PagesRead != 20 || book.Pages != 200 {
+		t.Errorf("expected patched fields to change and others to be kept, got %+v", book)
+	}
+
+	if resp := request(h, http.MethodDelete, "/api/books/book-three", "", map[string]string{"If-Match": "*"}); resp.Code != http.StatusNoContent {
+		t.Errorf("expected book to be deleted, got status %d", resp.Code)
+	}
+	if resp := request(h, http.MethodGet, "/api/books/book-three", "", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("expected deleted book to be gone, got status %d", resp.Code)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	resp := request(h, http.MethodPost, "/api/books", `{"id": "book-1", "title": "", "status": "lost"}`, nil)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected invalid book to be rejected, got status %d", resp.Code)
+	}
+
+	apiErr := resp.error(t)
+	var fields []string
+	for _, field := range apiErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	if apiErr.Status != http.StatusUnprocessableEntity || !reflect.DeepEqual(fields, []string{"title", "status"}) {
+		t.Errorf("expected invalid fields [title status], got %+v", apiErr)
+	}
+
+	for _, body := range []string{`{"title": `, `{"title": "Book", "unknown": true}`, `{"pages": "many"}`} {
+		if resp := request(h, http.MethodPost, "/api/books", body, nil); resp.Code != http.StatusBadRequest {
+			t.Errorf("expected body %s to be rejected, got status %d", body, resp.Code)
+		}
+	}
+
+	resp = request(h, http.MethodPatch, "/api/books/book-1", `{"id": "book-3"}`, map[string]string{"If-Match": "*"})
+	if resp.Code != http.StatusUnprocessableEntity || resp.error(t).Fields[0].Field != "id" {
+		t.Errorf("expected id change to be rejected, got status %d", resp.Code)
+	}
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	h, store := newTestHandler(t)
+
+	etag := request(h, http.MethodGet, "/api/books/book-1", "", nil).Header().Get("ETag")
+
+	if resp := request(h, http.MethodPatch, "/api/books/book-1", `{"rating": 4}`, nil); resp.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected patch without If-Match to be rejected, got status %d", resp.Code)
+	}
+
+	if resp := request(h, http.MethodPatch, "/api/books/book-1", `{"rating": 4}`, map[string]string{"If-Match": etag}); resp.Code != http.StatusOK {
+		t.Fatalf("expected patch with current ETag to succeed, got status %d", resp.Code)
+	}
+
+	// A second client still holding the old ETag
+	if resp := request(h, http.MethodPatch, "/api/books/book-1", `{"rating": 2}`, map[string]string{"If-Match": etag}); resp.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected patch with outdated ETag to be rejected, got status %d", resp.Code)
+	}
+	if resp := request(h, http.MethodDelete, "/api/books/book-1", "", map[string]string{"If-Match": etag}); resp.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected delete with outdated ETag to be rejected, got status %d", resp.Code)
+	}
+
+	if book, _ := store.Bookshelf().Book("book-1"); book.Rating != 4 {
+		t.Errorf("expected rating of the first patch, got %v", book.Rating)
+	}
+}
+
+func TestQuotes(t *testing.T) {
+	h, store := newTestHandler(t)
+
+	resp := request(h, http.MethodGet, "/api/books/book-1/quotes", "", nil)
+	var quotes []string
+	resp.data(t, &quotes)
+	if !reflect.DeepEqual(quotes, []string{"First.", "Second."}) {
+		t.Errorf("expected quotes of the book, got %v", quotes)
+	}
+	etag := resp.Header().Get("ETag")
+
+	resp = request(h, http.MethodPost, "/api/books/book-1/quotes", `{"quote": "Third."}`, nil)
+	if resp.Code != http.StatusCreated || resp.Header().Get("Location") != "/api/books/book-1/quotes/2" {
+		t.Fatalf("expected quote to be added, got status %d: %s", resp.Code, resp.Body.String())
+	}
+
+	if resp := request(h, http.MethodDelete, "/api/books/book-1/quotes/0", "", map[string]string{"If-Match": etag}); resp.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected delete with outdated quotes to be rejected, got status %d", resp.Code)
+	}
+
+	resp = request(h, http.MethodDelete, "/api/books/book-1/quotes/0", "", map[string]string{"If-Match": resp.Header().Get("ETag")})
+	if resp.Code != http.StatusNoContent {
+		t.Errorf("expected quote to be deleted, got status %d", resp.Code)
+	}
+
+	if book, _ := store.Bookshelf().Book("book-1"); !reflect.DeepEqual(dto.Texts(book.Quotes), []string{"Second.", "Third."}) {
+		t.Errorf("expected quotes [Second. Third.], got %v", book.Quotes)
+	}
+
+	if resp := request(h, http.MethodPost, "/api/books/book-1/quotes", `{"quote": " "}`, nil); resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected empty quote to be rejected, got status %d", resp.Code)
+	}
+	if resp := request(h, http.MethodDelete, "/api/books/book-1/quotes/9", "", map[string]string{"If-Match": "*"}); resp.Code != http.StatusNotFound {
+		t.Errorf("expected missing quote not to be found, got status %d", resp.Code)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	h, store := newTestHandler(t)
+
+	resp := request(h, http.MethodPost, "/api/collections", `{"name": "To Read Next", "books": ["book-2"]}`, nil)
+	if resp.Code != http.StatusCreated || resp.Header().Get("Location") != "/api/collections/To%20Read%20Next" {
+		t.Fatalf("expected collection to be created, got status %d: %s", resp.Code, resp.Body.String())
+	}
+
+	if resp := request(h, http.MethodPost, "/api/collections", `{"name": "Broken", "books": ["missing"]}`, nil); resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected unknown book to be rejected, got status %d", resp.Code)
+	}
+
+	etag := request(h, http.MethodGet, "/api/collections/To%20Read%20Next", "", nil).Header().Get("ETag")
+	resp = request(h, http.MethodPatch, "/api/collections/To%20Read%20Next", `{"name": "Next", "description": "Soon"}`, map[string]string{"If-Match": etag})
+	if resp.Code != http.StatusOK || resp.Header().Get("Location") != "/api/collections/Next" {
+		t.Fatalf("expected collection to be renamed, got status %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var collections []dto.Collection
+	request(h, http.MethodGet, "/api/collections", "", nil).data(t, &collections)
+	expected := []dto.Collection{{Name: "Favorites", Books: []string{"book-1"}}, {Name: "Next", Description: "Soon", Books: []string{"book-2"}}}
+	if !reflect.DeepEqual(collections, expected) {
+		t.Errorf("expected collections %+v, got %+v", expected, collections)
+	}
+
+	if resp := request(h, http.MethodDelete, "/api/collections/Next", "", map[string]string{"If-Match": "*"}); resp.Code != http.StatusNoContent {
+		t.Errorf("expected collection to be deleted, got status %d", resp.Code)
+	}
+	if len(store.Bookshelf().Collections) != 1 {
+		t.Errorf("expected one collection left, got %+v", store.Bookshelf().Collections)
+	}
+}
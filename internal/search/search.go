@@ -0,0 +1,138 @@
+// Package search builds the search index of the site, which is searched in
+// the browser without a server.
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"bookshelf/internal/dto"
+)
+
+// Version is the version of the index format, it increases with every
+// incompatible change.
+const Version = 1
+
+// Index lists the books and the tokens of their texts. Tokens are the prefixes
+// of one and two letters and the trigrams of every word, each mapped to the
+// positions of the books containing it in Books.
+type Index struct {
+	Version int              `json:"version"`
+	Books   []Book           `json:"books"`
+	Tokens  map[string][]int `json:"tokens"`
+}
+
+// Book is what a search result shows of a book.
+type Book struct {
+	Id      string   `json:"id"`
+	Title   string   `json:"title"`
+	Authors []string `json:"authors,omitempty"`
+}
+
+// New returns the index of the title, subtitle, authors, tags, genre, ISBN
+// and quotes of the books.
+func New(books []dto.Book) Index {
+	index := Index{
+		Version: Version,
+		Books:   make([]Book, 0, len(books)),
+		Tokens:  make(map[string][]int),
+	}
+
+	for i, book := range books {
+		index.Books = append(index.Books, Book{Id: book.Id, Title: book.Title, Authors: book.Authors})
+
+		texts := []string{book.Title, book.Subtitle, book.Genre, book.Isbn, strings.ReplaceAll(book.Isbn, "-", "")}
+		texts = append(texts, book.Authors...)
+		texts = append(texts, book.Tags...)
+		texts = append(texts, dto.Texts(book.Quotes)...)
+
+		seen := make(map[string]bool)
+		for _, text := range texts {
+			for _, word := range words(text) {
+				for _, token := range tokens(word) {
+					if !seen[token] {
+						seen[token] = true
+						index.Tokens[token] = append(index.Tokens[token], i)
+					}
+				}
+			}
+		}
+	}
+
+	return index
+}
+
+// words returns the lowercase words of the text, split at everything but
+// letters and numbers.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Search returns the books matching all words of the query in the order of
+// the index, the search box of the site matches them the same way: a word
+// shorter than three letters has to be the start of a word of the book, a
+// longer one has to share all its trigrams with the book.
+func (i Index) Search(query string) []Book {
+	var matches []int
+	first := true
+	for _, word := range words(query) {
+		for _, token := range queryTokens(word) {
+			if first {
+				matches = slices.Clone(i.Tokens[token])
+				first = false
+				continue
+			}
+
+			matches = slices.DeleteFunc(matches, func(book int) bool {
+				_, found := slices.BinarySearch(i.Tokens[token], book)
+				return !found
+			})
+		}
+	}
+
+	books := make([]Book, 0, len(matches))
+	for _, book := range matches {
+		books = append(books, i.Books[book])
+	}
+
+	return books
+}
+
+// queryTokens returns the tokens a book has to contain to match a word of a
+// query.
+func queryTokens(word string) []string {
+	runes := []rune(word)
+	if len(runes) < 3 {
+		return []string{word}
+	}
+
+	return trigrams(runes)
+}
+
+// tokens returns the prefixes of one and two letters and the trigrams of the
+// word.
+func tokens(word string) []string {
+	runes := []rune(word)
+
+	var tokens []string
+	for n := 1; n <= min(2, len(runes)); n++ {
+		tokens = append(tokens, string(runes[:n]))
+	}
+
+	return append(tokens, trigrams(runes)...)
+}
+
+func trigrams(runes []rune) []string {
+	var trigrams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !slices.Contains(trigrams, trigram) {
+			trigrams = append(trigrams, trigram)
+		}
+	}
+
+	return trigrams
+}
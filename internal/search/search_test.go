@@ -0,0 +1,84 @@
+package search
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bookshelf/internal/dto"
+)
+
+func createTestIndex() Index {
+	return New([]dto.Book{
+		{Id: "dune", Title: "Dune", Authors: []string{"Frank Herbert"}, Genre: "science-fiction", Isbn: "978-0-441-17271-9"},
+		{Id: "emma", Title: "Emma", Authors: []string{"Jane Austen"}, Tags: []string{"classic"}, Quotes: []dto.Entry{{Text: "Silly things do cease to be silly if they are done by sensible people."}}},
+		{Id: "der-zauberberg", Title: "Der Zauberberg", Subtitle: "Roman", Authors: []string{"Thomas Mann"}, Tags: []string{"classic"}},
+	})
+}
+
+func TestNew(t *testing.T) {
+	index := createTestIndex()
+
+	tests := map[string][]int{
+		"z":   {2},
+		"du":  {0},
+		"ber": {0, 2},
+		"cla": {1, 2},
+		"978": {0},
+		"441": {0},
+	}
+	for token, expected := range tests {
+		if books := index.Tokens[token]; !reflect.DeepEqual(books, expected) {
+			t.Errorf("expected token %q in books %v, got %v", token, expected, books)
+		}
+	}
+
+	if _, ok := index.Tokens["un"]; ok {
+		t.Error("expected only prefixes of words shorter than trigrams")
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatalf("could not marshal index: %v", err)
+	}
+
+	var decoded Index
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, index) {
+		t.Errorf("expected index to survive encoding (%v)", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	index := createTestIndex()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"dune", []string{"dune"}},
+		{"HERB", []string{"dune"}},
+		{"berg", []string{"der-zauberberg"}},
+		{"ber", []string{"dune", "der-zauberberg"}},
+		{"classic mann", []string{"der-zauberberg"}},
+		{"th ma", []string{"der-zauberberg"}},
+		{"sensible people", []string{"emma"}},
+		{"9780441172719", []string{"dune"}},
+		{"978-0-441", []string{"dune"}},
+		{"zauberberg dune", nil},
+		{"xyz", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var ids []string
+			for _, book := range index.Search(tt.query) {
+				ids = append(ids, book.Id)
+			}
+
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
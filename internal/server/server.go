@@ -0,0 +1,234 @@
+// Package server serves the site dynamically from the data in memory, so it can
+// be hosted without a static build.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/pages"
+	"bookshelf/internal/render"
+	"bookshelf/internal/storage"
+	"bookshelf/internal/watch"
+)
+
+// now is the clock deciding when the site is rendered again for a new day,
+// replaceable in tests.
+var now = time.Now
+
+type Config struct {
+	Site config.Config
+
+	// Renderer configures the templates, the site and features are taken from
+	// the site configuration
+	Renderer render.TemplateRendererConfig
+	Static   []fs.FS
+}
+
+type Server struct {
+	config  Config
+	storage storage.Storage
+
+	mu        sync.Mutex
+	bookshelf *dto.Bookshelf
+	site      *site
+}
+
+// site is a rendering of all pages and files, it is rendered on the first
+// request after the data changed and once a day for the date based stats.
+type site struct {
+	files    map[string]file
+	rendered time.Time
+	day      string
+}
+
+type file struct {
+	content []byte
+	etag    string
+}
+
+func New(config Config) (*Server, error) {
+	dataStorage, err := storage.Open(config.Site.Paths.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{config: config, storage: dataStorage}
+
+	err = s.Reload()
+	if err != nil {
+		dataStorage.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the storage of the data.
+func (s *Server) Close() error {
+	return s.storage.Close()
+}
+
+// Reload loads the data file, the site is rendered from it on the next request.
+// The current data is kept if the data file can not be loaded.
+func (s *Server) Reload() error {
+	bookshelf, err := s.storage.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bookshelf = bookshelf
+	s.site = nil
+
+	return nil
+}
+
+// Bookshelf returns the current data, it is replaced on changes and must not
+// be modified.
+func (s *Server) Bookshelf() *dto.Bookshelf {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bookshelf
+}
+
+// Update applies the changes to a copy of the current data and saves it to
+// the storage, the site is rendered from it on the next request. The data
+// stays unchanged if update or saving fails.
+func (s *Server) Update(update func(bookshelf *dto.Bookshelf) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookshelf := s.bookshelf.Clone()
+
+	err := update(bookshelf)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Save(bookshelf)
+	if err != nil {
+		return err
+	}
+
+	s.bookshelf = bookshelf
+	s.site = nil
+
+	return nil
+}
+
+// Watch reloads the data file whenever it changes until the context is done.
+func (s *Server) Watch(ctx context.Context, interval time.Duration) {
+	watch.New(s.config.Site.Paths.Data).Run(ctx, interval, func(changed []string) {
+		err := s.Reload()
+		if err != nil {
+			log.Printf("Failed to reload data, keeping the current data: %v", err)
+			return
+		}
+
+		log.Printf("Reloaded data from %s", s.config.Site.Paths.Data)
+	})
+}
+
+// ServeHTTP serves the same files as a static build of the site. Responses
+// carry an ETag and Last-Modified header, so unchanged files are not sent again.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" || strings.HasSuffix(r.URL.Path, "/") {
+		name = path.Join(name, "index.html")
+	}
+
+	current, err := s.current()
+	if err != nil {
+		log.Printf("Failed to render site: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	f, ok := current.files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, name, current.rendered, bytes.NewReader(f.content))
+}
+
+// current returns the rendering of the current data, rendering it if needed.
+func (s *Server) current() (*site, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := now().Format("2006-01-02")
+	if s.site != nil && s.site.day == today {
+		return s.site, nil
+	}
+
+	rendered, err := s.render()
+	if err != nil {
+		return nil, err
+	}
+	rendered.day = today
+	s.site = rendered
+
+	return rendered, nil
+}
+
+func (s *Server) render() (*site, error) {
+	// Private and draft books are only shown in the admin area and the API
+	bookshelf := s.bookshelf.Public()
+
+	rendererConfig := s.config.Renderer
+	rendererConfig.Site = s.config.Site.Site
+	rendererConfig.Features = s.config.Site.Features
+	rendererConfig.BookshelfPage = s.config.Site.BookshelfPage
+	rendererConfig.LastUpdated = bookshelf.LastModified()
+	rendererConfig.InMemory = true
+
+	renderer, err := render.New(rendererConfig)
+	if err != nil {
+		return nil, fmt.Errorf("initializing template renderer: %w", err)
+	}
+
+	err = renderer.CopyStaticFiles(s.config.Static...)
+	if err != nil {
+		return nil, fmt.Errorf("copying static files: %w", err)
+	}
+
+	err = pages.RenderSite(renderer, bookshelf, s.config.Site.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	// HTTP dates have a resolution of seconds
+	rendered := &site{files: make(map[string]file), rendered: now().Truncate(time.Second)}
+	for name, content := range renderer.Files() {
+		hash := sha256.Sum256(content)
+		rendered.files[name] = file{content: content, etag: `"` + hex.EncodeToString(hash[:16]) + `"`}
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/render"
+)
+
+const testData = `{"books": [
+	{"id": "book-1", "title": "Book One", "authors": ["Author A"], "status": "finished", "date_added": "2025-01-01"},
+	{"id": "book-2", "title": "Book Two", "authors": ["Author B"], "status": "to read", "date_added": "2025-02-01"}
+]}`
+
+func TestMain(m *testing.M) {
+	now = func() time.Time { return time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC) }
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T, data string) (*Server, string) {
+	t.Helper()
+
+	dataPath := filepath.Join(t.TempDir(), "data.json")
+	writeData(t, dataPath, data)
+
+	siteConfig := config.Default()
+	siteConfig.Paths.Data = dataPath
+
+	s, err := New(Config{
+		Site: siteConfig,
+		Renderer: render.TemplateRendererConfig{
+			TemplateType:           "html",
+			TemplateLayers:         []render.TemplateLayer{{Name: "default", FS: os.DirFS("../../templates")}},
+			ComponentTemplatesPath: "components",
+			PageTemplatesPath:      "pages",
+			BaseTemplateName:       "base",
+		},
+		Static: []fs.FS{fstest.MapFS{"css/style.css": {Data: []byte("body {}")}}},
+	})
+	if err != nil {
+		t.Fatalf("could not create server: %v", err)
+	}
+
+	return s, dataPath
+}
+
+func writeData(t *testing.T, path, data string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("could not write data file: %v", err)
+	}
+}
+
+func get(t *testing.T, handler http.Handler, target string, header map[string]string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Result()
+}
+
+func TestServeHTTP(t *testing.T) {
+	s, _ := newTestServer(t, testData)
+
+	tests := []struct {
+		target      string
+		status      int
+		contentType string
+	}{
+		{"/", http.StatusOK, "text/html; charset=utf-8"},
+		{"/index.html", http.StatusOK, "text/html; charset=utf-8"},
+		{"/book-1.html", http.StatusOK, "text/html; charset=utf-8"},
+		{"/css/style.css", http.StatusOK, "text/css; charset=utf-8"},
+		{"/api/books.json", http.StatusOK, "application/json"},
+		{"/feed.xml", http.StatusOK, "text/xml; charset=utf-8"},
+		{"/book-3.html", http.StatusNotFound, ""},
+		{"/../data.json", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			resp := get(t, s, tt.target, nil)
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.contentType != "" && resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("expected content type %s, got %s", tt.contentType, resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/index.html", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST to be rejected, got status %d", rec.Code)
+	}
+}
+
+func TestServeHTTP_Private(t *testing.T) {
+	s, _ := newTestServer(t, strings.Replace(testData, `"status": "to read"`, `"status": "to read", "visibility": "private"`, 1))
+
+	if resp := get(t, s, "/book-2.html", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected page of private book not to be served, got status %d", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(get(t, s, "/api/books.json", nil).Body); strings.Contains(string(body), "Book Two") {
+		t.Error("expected private book not to be listed")
+	}
+	if _, ok := s.Bookshelf().Book("book-2"); !ok {
+		t.Error("expected private book to be kept in the data")
+	}
+}
+
+func TestServeHTTP_ConditionalRequests(t *testing.T) {
+	s, _ := newTestServer(t, testData)
+
+	resp := get(t, s, "/bookshelf.html", nil)
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("expected ETag and Last-Modified headers, got %v", resp.Header)
+	}
+
+	if resp := get(t, s, "/bookshelf.html", map[string]string{"If-None-Match": etag}); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected matching ETag to be not modified, got status %d", resp.StatusCode)
+	}
+
+	if resp := get(t, s, "/bookshelf.html", map[string]string{"If-Modified-Since": lastModified}); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected unmodified page to be not modified, got status %d", resp.StatusCode)
+	}
+
+	if resp := get(t, s, "/bookshelf.html", map[string]string{"If-None-Match": `"outdated"`}); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected outdated ETag to be served, got status %d", resp.StatusCode)
+	}
+}
+
+func TestReload(t *testing.T) {
+	s, dataPath := newTestServer(t, testData)
+
+	etag := get(t, s, "/bookshelf.html", nil).Header.Get("ETag")
+
+	writeData(t, dataPath, strings.Replace(testData, `"id": "book-2", "title": "Book Two"`, `"id": "book-3", "title": "Book Three"`, 1))
+	if err := s.Reload(); err != nil {
+		t.Fatalf("could not reload data: %v", err)
+	}
+
+	resp := get(t, s, "/bookshelf.html", map[string]string{"If-None-Match": etag})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected changed page to be served, got status %d", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "Book Three") {
+		t.Error("expected page to be rendered from the reloaded data")
+	}
+
+	if resp := get(t, s, "/book-2.html", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected page of removed book to be gone, got status %d", resp.StatusCode)
+	}
+
+	// Broken data keeps the current data
+	writeData(t, dataPath, `{"books": [`)
+	if err := s.Reload(); err == nil {
+		t.Error("expected error reloading invalid data")
+	}
+	if resp := get(t, s, "/book-3.html", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected current data to be kept, got status %d", resp.StatusCode)
+	}
+}
+
+func TestServeHTTP_NewDay(t *testing.T) {
+	s, _ := newTestServer(t, testData)
+	defer func(previous func() time.Time) { now = previous }(now)
+
+	get(t, s, "/index.html", nil)
+	rendered := s.site
+
+	get(t, s, "/index.html", nil)
+	if s.site != rendered {
+		t.Error("expected the site to be rendered once a day")
+	}
+
+	now = func() time.Time { return time.Date(2025, 11, 21, 0, 0, 1, 0, time.UTC) }
+	get(t, s, "/index.html", nil)
+	if s.site == rendered {
+		t.Error("expected the site to be rendered again on a new day")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s, dataPath := newTestServer(t, testData)
+
+	err := s.Update(func(bookshelf *dto.Bookshelf) error {
+		_, err := bookshelf.AddBook(dto.Book{Title: "Book Three", Status: dto.StatusReading})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("could not update data: %v", err)
+	}
+
+	if resp := get(t, s, "/book-three.html", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected page of added book to be served, got status %d", resp.StatusCode)
+	}
+
+	saved, err := dto.LoadBookshelfFromFile(dataPath)
+	if err != nil {
+		t.Fatalf("could not load saved data: %v", err)
+	}
+	if _, ok := saved.Book("book-three"); !ok {
+		t.Error("expected added book to be written to the data file")
+	}
+
+	// Failed updates keep the current data
+	err = s.Update(func(bookshelf *dto.Bookshelf) error {
+		bookshelf.DeleteBook("book-1")
+		return bookshelf.DeleteBook("missing")
+	})
+	if !errors.Is(err, dto.ErrBookNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, ok := s.Bookshelf().Book("book-1"); !ok {
+		t.Error("expected failed update to be discarded")
+	}
+}
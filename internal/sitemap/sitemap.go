@@ -0,0 +1,37 @@
+// Package sitemap encodes the sitemap.xml and robots.txt of the site.
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type URL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []URL    `xml:"url"`
+}
+
+// Encode returns the sitemap of the given absolute URLs, the last modification
+// dates are expected in the "yyyy-mm-dd" format.
+func Encode(urls []URL) ([]byte, error) {
+	data, err := xml.MarshalIndent(urlSet{XMLNS: namespace, URLs: urls}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Robots returns a robots.txt allowing all crawlers on the given path and
+// pointing them to the sitemap.
+func Robots(allowPath, sitemapURL string) []byte {
+	return []byte(fmt.Sprintf("User-agent: *\nAllow: %s\n\nSitemap: %s\n", allowPath, sitemapURL))
+}
@@ -0,0 +1,44 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/bookshelf/index.html", LastMod: "2025-11-29"},
+		{Loc: "https://example.com/bookshelf/a&b.html"},
+	}
+
+	data, err := Encode(urls)
+	if err != nil {
+		t.Fatalf("could not encode sitemap: %v", err)
+	}
+
+	var decoded urlSet
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("could not decode sitemap: %v", err)
+	}
+
+	if decoded.XMLName.Space != namespace {
+		t.Errorf("expected sitemap namespace, got %s", decoded.XMLName.Space)
+	}
+
+	if len(decoded.URLs) != 2 {
+		t.Fatalf("expected 2 urls, got %d", len(decoded.URLs))
+	}
+
+	if decoded.URLs[0] != urls[0] || decoded.URLs[1] != urls[1] {
+		t.Errorf("expected urls to survive encoding, got %+v", decoded.URLs)
+	}
+}
+
+func TestRobots(t *testing.T) {
+	robots := string(Robots("/bookshelf/", "https://example.com/bookshelf/sitemap.xml"))
+
+	expected := "User-agent: *\nAllow: /bookshelf/\n\nSitemap: https://example.com/bookshelf/sitemap.xml\n"
+	if robots != expected {
+		t.Errorf("expected %q, got %q", expected, robots)
+	}
+}
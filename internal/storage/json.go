@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"bookshelf/internal/dto"
+)
+
+// JSON stores the bookshelf in a JSON file.
+type JSON struct {
+	path string
+}
+
+func OpenJSON(path string) *JSON {
+	return &JSON{path: path}
+}
+
+func (s *JSON) Load() (*dto.Bookshelf, error) {
+	return dto.LoadBookshelfFromFile(s.path)
+}
+
+func (s *JSON) Save(bookshelf *dto.Bookshelf) error {
+	return dto.SaveBookshelfToFile(s.path, bookshelf)
+}
+
+func (s *JSON) Close() error {
+	return nil
+}
@@ -0,0 +1,499 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"bookshelf/internal/dto"
+)
+
+// schemaVersion is stored as user_version of the database, so later versions
+// of the schema can migrate older databases.
+const schemaVersion = 2
+
+// schema keeps the order of books, collections and all lists in a position
+// column, so a bookshelf is loaded exactly as it was saved.
+const schema = `
+CREATE TABLE books (
+	id            TEXT PRIMARY KEY,
+	position      INTEGER NOT NULL,
+	isbn          TEXT NOT NULL,
+	title         TEXT NOT NULL,
+	subtitle      TEXT NOT NULL,
+	year          INTEGER NOT NULL,
+	language      TEXT NOT NULL,
+	pages         INTEGER NOT NULL,
+	genre         TEXT NOT NULL,
+	cover         TEXT NOT NULL,
+	link          TEXT NOT NULL,
+	date_added    TEXT NOT NULL,
+	status        TEXT NOT NULL,
+	rank          INTEGER NOT NULL,
+	rating        REAL NOT NULL,
+	date_started  TEXT NOT NULL,
+	date_finished TEXT NOT NULL,
+	pages_read    INTEGER NOT NULL,
+	visibility    TEXT NOT NULL
+);
+
+CREATE TABLE book_authors (
+	book_id  TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	value    TEXT NOT NULL,
+	PRIMARY KEY (book_id, position)
+);
+
+CREATE TABLE book_tags (
+	book_id  TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	value    TEXT NOT NULL,
+	PRIMARY KEY (book_id, position)
+);
+
+CREATE TABLE book_review (
+	book_id    TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+	position   INTEGER NOT NULL,
+	value      TEXT NOT NULL,
+	visibility TEXT NOT NULL,
+	PRIMARY KEY (book_id, position)
+);
+
+CREATE TABLE book_quotes (
+	book_id    TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+	position   INTEGER NOT NULL,
+	value      TEXT NOT NULL,
+	visibility TEXT NOT NULL,
+	PRIMARY KEY (book_id, position)
+);
+
+CREATE TABLE reading_sessions (
+	book_id  TEXT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	date     TEXT NOT NULL,
+	pages    INTEGER NOT NULL,
+	PRIMARY KEY (book_id, position)
+);
+
+CREATE TABLE collections (
+	name        TEXT PRIMARY KEY,
+	position    INTEGER NOT NULL,
+	description TEXT NOT NULL
+);
+
+-- Collections may reference books which do not exist, they are skipped when
+-- the collections are shown
+CREATE TABLE collection_books (
+	collection_name TEXT NOT NULL REFERENCES collections (name) ON DELETE CASCADE,
+	position        INTEGER NOT NULL,
+	book_id         TEXT NOT NULL,
+	PRIMARY KEY (collection_name, position)
+);
+`
+
+// migrations upgrade the schema of older databases, migrations[i] upgrades
+// version i+1 to version i+2.
+var migrations = []string{
+	// Visibility of books, review paragraphs and quotes
+	`ALTER TABLE books ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
+	ALTER TABLE book_review ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
+	ALTER TABLE book_quotes ADD COLUMN visibility TEXT NOT NULL DEFAULT '';`,
+}
+
+// listTables are the tables of the string lists of a book.
+var listTables = []string{"book_authors", "book_tags"}
+
+// entryTables are the tables of the review paragraphs and quotes of a book.
+var entryTables = []string{"book_review", "book_quotes"}
+
+// SQLite stores the bookshelf in an SQLite database.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens an existing database.
+func OpenSQLite(path string) (*SQLite, error) {
+	return openSQLite(path, "rw")
+}
+
+// CreateSQLite opens the database, it is created if it does not exist.
+func CreateSQLite(path string) (*SQLite, error) {
+	return openSQLite(path, "rwc")
+}
+
+func openSQLite(path, mode string) (*SQLite, error) {
+	dsn, err := sqliteDSN(path, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	s := &SQLite{db: db}
+
+	err = s.migrate()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// sqliteDSN returns the URI of the database file, so paths containing "?" or
+// "#" are not mistaken for its parameters.
+func sqliteDSN(path, mode string) (string, error) {
+	// A relative path would be taken as the host of the URI
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("mode", mode)
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+
+	dsn := url.URL{Scheme: "file", Path: filepath.ToSlash(path), RawQuery: query.Encode()}
+	if !strings.HasPrefix(dsn.Path, "/") {
+		// Windows paths start with the drive letter
+		dsn.Path = "/" + dsn.Path
+	}
+
+	return dsn.String(), nil
+}
+
+// migrate creates the schema of a new database or upgrades the schema of an
+// older one.
+func (s *SQLite) migrate() error {
+	var version int
+	err := s.db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+
+	switch {
+	case version == schemaVersion:
+		return nil
+	case version > schemaVersion:
+		return fmt.Errorf("unsupported schema version %d, the database was created by a newer version", version)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if version == 0 {
+		_, err = tx.Exec(schema)
+		if err != nil {
+			return fmt.Errorf("creating schema: %w", err)
+		}
+	}
+	for ; version > 0 && version < schemaVersion; version++ {
+		_, err = tx.Exec(migrations[version-1])
+		if err != nil {
+			return fmt.Errorf("upgrading schema version %d: %w", version, err)
+		}
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion))
+	if err != nil {
+		return fmt.Errorf("writing schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLite) Load() (*dto.Bookshelf, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	books, err := loadBooks(tx)
+	if err != nil {
+		return nil, fmt.Errorf("loading books: %w", err)
+	}
+
+	collections, err := loadCollections(tx)
+	if err != nil {
+		return nil, fmt.Errorf("loading collections: %w", err)
+	}
+
+	return &dto.Bookshelf{Books: books, Collections: collections}, nil
+}
+
+func loadBooks(tx *sql.Tx) ([]dto.Book, error) {
+	rows, err := tx.Query(`SELECT id, isbn, title, subtitle, year, language, pages, genre, cover, link, date_added,
+		status, rank, rating, date_started, date_finished, pages_read, visibility FROM books ORDER BY position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []dto.Book
+	for rows.Next() {
+		var book dto.Book
+		err := rows.Scan(&book.Id, &book.Isbn, &book.Title, &book.Subtitle, &book.Year, &book.Language, &book.Pages,
+			&book.Genre, &book.Cover, &book.Link, &book.DateAdded, &book.Status, &book.Rank, &book.Rating,
+			&book.Progress.DateStarted, &book.Progress.DateFinished, &book.Progress.PagesRead, &book.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lists := make(map[string]map[string][]string, len(listTables))
+	for _, table := range listTables {
+		lists[table], err = loadLists(tx, table)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make(map[string]map[string][]dto.Entry, len(entryTables))
+	for _, table := range entryTables {
+		entries[table], err = loadEntries(tx, table)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sessions, err := loadSessions(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range books {
+		id := books[i].Id
+		books[i].Authors = lists["book_authors"][id]
+		books[i].Tags = lists["book_tags"][id]
+		books[i].Review = entries["book_review"][id]
+		books[i].Quotes = entries["book_quotes"][id]
+		books[i].Progress.Sessions = sessions[id]
+	}
+
+	return books, nil
+}
+
+// loadLists loads the lists of a list table by book id.
+func loadLists(tx *sql.Tx, table string) (map[string][]string, error) {
+	rows, err := tx.Query("SELECT book_id, value FROM " + table + " ORDER BY book_id, position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make(map[string][]string)
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+		lists[id] = append(lists[id], value)
+	}
+
+	return lists, rows.Err()
+}
+
+// loadEntries loads the entries of an entry table by book id.
+func loadEntries(tx *sql.Tx, table string) (map[string][]dto.Entry, error) {
+	rows, err := tx.Query("SELECT book_id, value, visibility FROM " + table + " ORDER BY book_id, position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make(map[string][]dto.Entry)
+	for rows.Next() {
+		var id string
+		var entry dto.Entry
+		if err := rows.Scan(&id, &entry.Text, &entry.Visibility); err != nil {
+			return nil, err
+		}
+		entries[id] = append(entries[id], entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func loadSessions(tx *sql.Tx) (map[string][]dto.Session, error) {
+	rows, err := tx.Query("SELECT book_id, date, pages FROM reading_sessions ORDER BY book_id, position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make(map[string][]dto.Session)
+	for rows.Next() {
+		var id string
+		var session dto.Session
+		if err := rows.Scan(&id, &session.Date, &session.Pages); err != nil {
+			return nil, err
+		}
+		sessions[id] = append(sessions[id], session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func loadCollections(tx *sql.Tx) ([]dto.Collection, error) {
+	rows, err := tx.Query("SELECT name, description FROM collections ORDER BY position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []dto.Collection
+	for rows.Next() {
+		var collection dto.Collection
+		if err := rows.Scan(&collection.Name, &collection.Description); err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	bookRows, err := tx.Query("SELECT collection_name, book_id FROM collection_books ORDER BY collection_name, position")
+	if err != nil {
+		return nil, err
+	}
+	defer bookRows.Close()
+
+	books := make(map[string][]string)
+	for bookRows.Next() {
+		var name, id string
+		if err := bookRows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		books[name] = append(books[name], id)
+	}
+	if err := bookRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range collections {
+		collections[i].Books = books[collections[i].Name]
+	}
+
+	return collections, nil
+}
+
+// Save replaces all stored books and collections in a single transaction.
+func (s *SQLite) Save(bookshelf *dto.Bookshelf) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lists and sessions are deleted with their books and collections
+	for _, table := range []string{"books", "collections"} {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("deleting %s: %w", table, err)
+		}
+	}
+
+	for position, book := range bookshelf.Books {
+		err := saveBook(tx, position, book)
+		if err != nil {
+			return fmt.Errorf("saving book %s: %w", book.Id, err)
+		}
+	}
+
+	for position, collection := range bookshelf.Collections {
+		err := saveCollection(tx, position, collection)
+		if err != nil {
+			return fmt.Errorf("saving collection %s: %w", collection.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func saveBook(tx *sql.Tx, position int, book dto.Book) error {
+	_, err := tx.Exec(`INSERT INTO books (id, position, isbn, title, subtitle, year, language, pages, genre, cover, link,
+		date_added, status, rank, rating, date_started, date_finished, pages_read, visibility)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		book.Id, position, book.Isbn, book.Title, book.Subtitle, book.Year, book.Language, book.Pages, book.Genre,
+		book.Cover, book.Link, book.DateAdded, book.Status, book.Rank, book.Rating,
+		book.Progress.DateStarted, book.Progress.DateFinished, book.Progress.PagesRead, book.Visibility)
+	if err != nil {
+		return err
+	}
+
+	lists := map[string][]string{
+		"book_authors": book.Authors,
+		"book_tags":    book.Tags,
+	}
+	for _, table := range listTables {
+		for i, value := range lists[table] {
+			_, err := tx.Exec("INSERT INTO "+table+" (book_id, position, value) VALUES (?, ?, ?)", book.Id, i, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	entries := map[string][]dto.Entry{
+		"book_review": book.Review,
+		"book_quotes": book.Quotes,
+	}
+	for _, table := range entryTables {
+		for i, entry := range entries[table] {
+			_, err := tx.Exec("INSERT INTO "+table+" (book_id, position, value, visibility) VALUES (?, ?, ?, ?)",
+				book.Id, i, entry.Text, entry.Visibility)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, session := range book.Progress.Sessions {
+		_, err := tx.Exec("INSERT INTO reading_sessions (book_id, position, date, pages) VALUES (?, ?, ?, ?)",
+			book.Id, i, session.Date, session.Pages)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveCollection(tx *sql.Tx, position int, collection dto.Collection) error {
+	_, err := tx.Exec("INSERT INTO collections (name, position, description) VALUES (?, ?, ?)",
+		collection.Name, position, collection.Description)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range collection.Books {
+		_, err := tx.Exec("INSERT INTO collection_books (collection_name, position, book_id) VALUES (?, ?, ?)",
+			collection.Name, i, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,102 @@
+// Package storage loads and saves the bookshelf, so the data can be kept in a
+// JSON file or an SQLite database.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bookshelf/internal/dto"
+)
+
+// Storage loads and saves all books, their reading sessions and collections.
+type Storage interface {
+	Load() (*dto.Bookshelf, error)
+
+	// Save replaces the stored bookshelf, it is saved completely or not at
+	// all
+	Save(bookshelf *dto.Bookshelf) error
+
+	Close() error
+}
+
+// Open opens the storage of an existing data path by its extension, ".db",
+// ".sqlite" and ".sqlite3" are SQLite databases, all other paths JSON files.
+// A missing data file is an error, so a mistyped path does not turn into an
+// empty bookshelf.
+func Open(path string) (Storage, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("data file %s not found", path)
+		}
+		return nil, fmt.Errorf("opening data file: %w", err)
+	}
+
+	return open(path, OpenSQLite)
+}
+
+// Create opens the storage of the data path like Open, the data file is
+// created if it does not exist, e.g. as the target of a migration.
+func Create(path string) (Storage, error) {
+	return open(path, CreateSQLite)
+}
+
+func open(path string, openSQLite func(path string) (*SQLite, error)) (Storage, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		storage, err := openSQLite(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening SQLite database: %w", err)
+		}
+		return storage, nil
+	default:
+		return OpenJSON(path), nil
+	}
+}
+
+// Load loads the bookshelf of the data path.
+func Load(path string) (*dto.Bookshelf, error) {
+	storage, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+
+	return storage.Load()
+}
+
+// Migrate copies the bookshelf of one data file to another, replacing the
+// bookshelf stored in the target. The source is loaded before the target is
+// opened, so the target is left untouched if the source is missing or can not
+// be loaded. The target is created if it does not exist.
+func Migrate(from, to string) (*dto.Bookshelf, error) {
+	source, err := Open(from)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	bookshelf, err := source.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading bookshelf: %w", err)
+	}
+
+	target, err := Create(to)
+	if err != nil {
+		return nil, err
+	}
+
+	err = target.Save(bookshelf)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("saving bookshelf: %w", err)
+	}
+
+	return bookshelf, nil
+}
@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"bookshelf/internal/dto"
+)
+
+// backends open every implementation of Storage at a path in the directory,
+// all of them have to pass the conformance tests.
+var backends = map[string]func(t *testing.T, dir string) Storage{
+	"json": func(t *testing.T, dir string) Storage {
+		return OpenJSON(filepath.Join(dir, "data.json"))
+	},
+	"sqlite": func(t *testing.T, dir string) Storage {
+		storage, err := CreateSQLite(filepath.Join(dir, "data.db"))
+		if err != nil {
+			t.Fatalf("could not open database: %v", err)
+		}
+		return storage
+	},
+}
+
+func createTestBookshelf() *dto.Bookshelf {
+	return &dto.Bookshelf{
+		Books: []dto.Book{
+			{
+				Id: "book-2", Isbn: "9780000000002", Title: "Book Two", Subtitle: "A Subtitle",
+				Authors: []string{"Author B", "Author A"}, Year: 1999, Language: "en", Pages: 300,
+				Genre: "fantasy", Tags: []string{"magic", "dragons"}, Cover: "https://example.com/cover.jpg",
+				Link: "https://example.com", DateAdded: "2025-01-01", Status: dto.StatusFinished, Rating: 4.5,
+				Review: []dto.Entry{{Text: "First paragraph."}, {Text: "Second paragraph."}}, Quotes: []dto.Entry{{Text: "A quote."}, {Text: "Another quote.", Visibility: dto.VisibilityPrivate}},
+				Progress: dto.Progress{
+					DateStarted: "2025-01-02", DateFinished: "2025-01-10", PagesRead: 300,
+					Sessions: []dto.Session{{Date: "2025-01-02", Pages: 100}, {Date: "2025-01-10", Pages: 200}},
+				},
+			},
+			{Id: "book-1", Title: "Book One", Authors: []string{"Author A"}, Status: dto.StatusWishlisted, Rank: 1},
+			{Id: "book-3", Title: "Book Three", Status: dto.StatusReading, Progress: dto.Progress{DateStarted: "2025"}},
+		},
+		Collections: []dto.Collection{
+			{Name: "Favorites", Description: "The best ones", Books: []string{"book-3", "book-2"}},
+			{Name: "Empty"},
+			{Name: "Dangling", Books: []string{"missing"}},
+		},
+	}
+}
+
+func TestConformance(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Run("RoundTrip", func(t *testing.T) {
+				storage := open(t, t.TempDir())
+				defer storage.Close()
+
+				bookshelf := createTestBookshelf()
+				if err := storage.Save(bookshelf); err != nil {
+					t.Fatalf("could not save bookshelf: %v", err)
+				}
+
+				loaded, err := storage.Load()
+				if err != nil {
+					t.Fatalf("could not load bookshelf: %v", err)
+				}
+				if !reflect.DeepEqual(loaded, bookshelf) {
+					t.Errorf("expected bookshelf to be loaded as saved, got %+v", loaded)
+				}
+			})
+
+			t.Run("SaveReplaces", func(t *testing.T) {
+				storage := open(t, t.TempDir())
+				defer storage.Close()
+
+				storage.Save(createTestBookshelf())
+
+				bookshelf := createTestBookshelf()
+				bookshelf.DeleteBook("book-2")
+				bookshelf.DeleteCollection("Empty")
+				bookshelf.AddQuote("book-3", dto.Entry{Text: "A new quote."})
+				if err := storage.Save(bookshelf); err != nil {
+					t.Fatalf("could not save bookshelf: %v", err)
+				}
+
+				loaded, err := storage.Load()
+				if err != nil {
+					t.Fatalf("could not load bookshelf: %v", err)
+				}
+				if !reflect.DeepEqual(loaded, bookshelf) {
+					t.Errorf("expected saved bookshelf to replace the previous one, got %+v", loaded)
+				}
+			})
+
+			t.Run("Reopen", func(t *testing.T) {
+				dir := t.TempDir()
+				storage := open(t, dir)
+				storage.Save(createTestBookshelf())
+				if err := storage.Close(); err != nil {
+					t.Fatalf("could not close storage: %v", err)
+				}
+
+				storage = open(t, dir)
+				defer storage.Close()
+
+				loaded, err := storage.Load()
+				if err != nil {
+					t.Fatalf("could not load bookshelf: %v", err)
+				}
+				if !reflect.DeepEqual(loaded, createTestBookshelf()) {
+					t.Errorf("expected bookshelf to be kept after closing, got %+v", loaded)
+				}
+			})
+
+			t.Run("Empty", func(t *testing.T) {
+				storage := open(t, t.TempDir())
+				defer storage.Close()
+
+				if err := storage.Save(&dto.Bookshelf{}); err != nil {
+					t.Fatalf("could not save bookshelf: %v", err)
+				}
+
+				loaded, err := storage.Load()
+				if err != nil {
+					t.Fatalf("could not load bookshelf: %v", err)
+				}
+				if len(loaded.Books) != 0 || len(loaded.Collections) != 0 {
+					t.Errorf("expected empty bookshelf, got %+v", loaded)
+				}
+			})
+		})
+	}
+}
+
+func TestSQLite_DuplicateBookKeepsData(t *testing.T) {
+	storage, err := CreateSQLite(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+	defer storage.Close()
+
+	storage.Save(createTestBookshelf())
+
+	broken := createTestBookshelf()
+	broken.Books = append(broken.Books, broken.Books[0])
+	if err := storage.Save(broken); err == nil {
+		t.Fatal("expected error saving duplicate book ids")
+	}
+
+	loaded, err := storage.Load()
+	if err != nil {
+		t.Fatalf("could not load bookshelf: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, createTestBookshelf()) {
+		t.Error("expected failed save to keep the stored bookshelf")
+	}
+}
+
+func TestSQLite_UpgradeSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	storage, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+
+	bookshelf := &dto.Bookshelf{Books: []dto.Book{{Id: "book-1", Title: "Book One", Quotes: []dto.Entry{{Text: "A quote."}}}}}
+	storage.Save(bookshelf)
+
+	// Turn the database into one of the first schema version
+	_, err = storage.db.Exec(`ALTER TABLE books DROP COLUMN visibility;
+		ALTER TABLE book_review DROP COLUMN visibility;
+		ALTER TABLE book_quotes DROP COLUMN visibility;
+		PRAGMA user_version = 1;`)
+	if err != nil {
+		t.Fatalf("could not downgrade schema: %v", err)
+	}
+	storage.Close()
+
+	storage, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("could not open database of an older schema: %v", err)
+	}
+	defer storage.Close()
+
+	loaded, err := storage.Load()
+	if err != nil {
+		t.Fatalf("could not load bookshelf: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, bookshelf) {
+		t.Errorf("expected bookshelf to be kept by the upgrade, got %+v", loaded)
+	}
+
+	var version int
+	if err := storage.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != schemaVersion {
+		t.Errorf("expected schema version %d, got %d (%v)", schemaVersion, version, err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		"data.json":    "*storage.JSON",
+		"data.db":      "*storage.SQLite",
+		"data.sqlite":  "*storage.SQLite",
+		"DATA.SQLITE3": "*storage.SQLite",
+	}
+
+	for name, expected := range tests {
+		path := filepath.Join(dir, name)
+		if _, err := Open(path); err == nil {
+			t.Errorf("expected error opening the missing %s", name)
+		}
+
+		created, err := Create(path)
+		if err != nil {
+			t.Fatalf("could not create %s: %v", name, err)
+		}
+		created.Save(&dto.Bookshelf{})
+		created.Close()
+
+		storage, err := Open(path)
+		if err != nil {
+			t.Fatalf("could not open %s: %v", name, err)
+		}
+		if got := reflect.TypeOf(storage).String(); got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, name, got)
+		}
+		storage.Close()
+	}
+}
+
+func TestOpenSQLite_Path(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "books?v=1#1")
+	if err := os.Mkdir(dir, os.ModePerm); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	path := filepath.Join(dir, "data.db")
+	if _, err := OpenSQLite(path); err == nil {
+		t.Error("expected error opening a missing database")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no database to be created by opening it, got %v", err)
+	}
+
+	storage, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatalf("could not create database: %v", err)
+	}
+	storage.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the database at the path with ? and #: %v", err)
+	}
+}
+
+func TestJSON_SaveEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := OpenJSON(path).Save(&dto.Bookshelf{}); err != nil {
+		t.Fatalf("could not save bookshelf: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "{\n  \"books\": [],\n  \"collections\": []\n}\n" {
+		t.Errorf("expected empty lists to be written as [], got %s", data)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "data.json")
+	OpenJSON(jsonPath).Save(createTestBookshelf())
+	original, _ := os.ReadFile(jsonPath)
+
+	databasePath := filepath.Join(dir, "data.db")
+	if _, err := Migrate(jsonPath, databasePath); err != nil {
+		t.Fatalf("could not migrate to SQLite: %v", err)
+	}
+
+	migratedPath := filepath.Join(dir, "migrated.json")
+	if _, err := Migrate(databasePath, migratedPath); err != nil {
+		t.Fatalf("could not migrate to JSON: %v", err)
+	}
+
+	migrated, _ := os.ReadFile(migratedPath)
+	if string(migrated) != string(original) {
+		t.Errorf("expected the JSON file to be unchanged after migrating back and forth, got %s", migrated)
+	}
+}
+
+func TestMigrate_MissingSource(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "data.json")
+	OpenJSON(jsonPath).Save(createTestBookshelf())
+	original, _ := os.ReadFile(jsonPath)
+
+	for _, source := range []string{"typo.db", "typo.json"} {
+		if _, err := Migrate(filepath.Join(dir, source), jsonPath); err == nil {
+			t.Errorf("expected error migrating the missing %s", source)
+		}
+	}
+	if data, _ := os.ReadFile(jsonPath); string(data) != string(original) {
+		t.Errorf("expected the target to be untouched, got %s", data)
+	}
+
+	databasePath := filepath.Join(dir, "data.db")
+	if _, err := Migrate(filepath.Join(dir, "typo.json"), databasePath); err == nil {
+		t.Error("expected error migrating a missing file")
+	}
+	if _, err := os.Stat(databasePath); !os.IsNotExist(err) {
+		t.Errorf("expected no database to be created for a missing source, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "typo.db")); !os.IsNotExist(err) {
+		t.Errorf("expected the missing source not to be created, got %v", err)
+	}
+}
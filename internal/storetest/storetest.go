@@ -0,0 +1,42 @@
+// Package storetest provides a store keeping the bookshelf in memory for the
+// tests of the handlers changing it.
+package storetest
+
+import (
+	"sync"
+
+	"bookshelf/internal/dto"
+)
+
+// Store implements dto.Store without saving the changes anywhere.
+type Store struct {
+	mu        sync.Mutex
+	bookshelf *dto.Bookshelf
+}
+
+func New(bookshelf *dto.Bookshelf) *Store {
+	return &Store{bookshelf: bookshelf}
+}
+
+func (s *Store) Bookshelf() *dto.Bookshelf {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bookshelf
+}
+
+func (s *Store) Update(update func(bookshelf *dto.Bookshelf) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookshelf := s.bookshelf.Clone()
+
+	err := update(bookshelf)
+	if err != nil {
+		return err
+	}
+
+	s.bookshelf = bookshelf
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package watch detects changes to files by polling, so it works the same on
+// every platform without file system notifications.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type Watcher struct {
+	paths []string
+	files map[string]fileState
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// New returns a watcher of the given files and directories, directories are
+// watched recursively. Paths which do not exist yet are watched as well.
+func New(paths ...string) *Watcher {
+	w := &Watcher{paths: paths}
+	w.files = w.snapshot()
+
+	return w
+}
+
+// Changes returns the files added, modified or removed since the last call, in
+// alphabetical order.
+func (w *Watcher) Changes() []string {
+	files := w.snapshot()
+
+	var changed []string
+	for path, state := range files {
+		if previous, ok := w.files[path]; !ok || previous != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := files[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	w.files = files
+
+	return changed
+}
+
+// Run polls for changes in the given interval until the context is done and
+// calls onChange with the changed files.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, onChange func(changed []string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed := w.Changes(); len(changed) > 0 {
+				onChange(changed)
+			}
+		}
+	}
+}
+
+func (w *Watcher) snapshot() map[string]fileState {
+	files := make(map[string]fileState)
+
+	for _, root := range w.paths {
+		// Paths missing or removed while walking are skipped, their files count
+		// as removed until they show up again
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+
+			return nil
+		})
+	}
+
+	return files
+}
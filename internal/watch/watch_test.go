@@ -0,0 +1,57 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.json")
+	templates := filepath.Join(dir, "templates")
+	style := filepath.Join(dir, "static", "style.css")
+
+	writeFile(t, data, "{}")
+	writeFile(t, filepath.Join(templates, "base.html"), "base")
+	writeFile(t, filepath.Join(templates, "pages", "index.html"), "index")
+
+	watcher := New(data, templates, filepath.Join(dir, "static"))
+
+	if changed := watcher.Changes(); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+
+	// Modification times may not change within the resolution of the file system
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(data, past, past); err != nil {
+		t.Fatalf("could not change file time: %v", err)
+	}
+	writeFile(t, filepath.Join(templates, "pages", "index.html"), "changed index")
+	writeFile(t, style, "body {}")
+	if err := os.Remove(filepath.Join(templates, "base.html")); err != nil {
+		t.Fatalf("could not remove file: %v", err)
+	}
+
+	expected := []string{data, style, filepath.Join(templates, "base.html"), filepath.Join(templates, "pages", "index.html")}
+	if changed := watcher.Changes(); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected changes %v, got %v", expected, changed)
+	}
+
+	if changed := watcher.Changes(); len(changed) != 0 {
+		t.Errorf("expected changes to be reported once, got %v", changed)
+	}
+}
@@ -1,67 +1,73 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
+	"os"
 
-	"bookshelf/internal/dto"
-	"bookshelf/internal/pages"
+	"bookshelf/internal/config"
 	"bookshelf/internal/render"
 )
 
-func main() {
-	bookshelf, err := dto.LoadBookshelfFromFile("data/data.json")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	config := render.TemplateRendererConfig{
-		TemplateType:           "html",
-		TemplatesPath:          "templates",
-		ComponentTemplatesPath: "components",
-		PageTemplatesPath:      "pages",
-		OutputPath:             "dist",
-		BaseTemplateName:       "base",
-	}
+const defaultConfigPath = "config.json"
 
-	renderer, err := render.New(config)
-	if err != nil {
-		log.Fatalf("Failed to initialize template renderer: %v", err)
-	}
-
-	err = renderer.CopyStaticFiles("static", config.OutputPath)
-	if err != nil {
-		log.Fatalf("Failed to copy static files: %v", err)
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			serve(os.Args[2:])
+			return
+		case "server":
+			runServer(os.Args[2:])
+			return
+		case "hash-password":
+			hashPassword(os.Args[2:])
+			return
+		case "generate-token":
+			generateToken(os.Args[2:])
+			return
+		case "migrate":
+			migrate(os.Args[2:])
+			return
+		}
 	}
 
-	err = pages.RenderIndexPage(renderer, bookshelf)
-	if err != nil {
-		log.Fatalf("Failed to render index page: %v", err)
-	}
+	configPath := flag.String("config", defaultConfigPath, "path of the site configuration file")
+	workers := flag.Int("workers", 0, "number of pages rendered concurrently, defaults to the number of CPUs")
+	clean := flag.Bool("clean", false, "remove the output of previous builds and build from scratch")
+	includePrivate := flag.Bool("include-private", false, "include private and draft books, reviews and quotes in a preview built into the output path with a -private suffix")
+	flag.Parse()
 
-	err = pages.RenderBookshelfPage(renderer, bookshelf)
+	siteConfig, err := loadConfig(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to render bookshelf page: %v", err)
+		log.Fatal(err)
 	}
 
-	err = pages.RenderCollectionsPage(renderer, bookshelf)
-	if err != nil {
-		log.Fatalf("Failed to render collections page: %v", err)
+	if *clean {
+		err = render.Clean(buildOutputPath(siteConfig.Paths, *includePrivate))
+		if err != nil {
+			log.Fatalf("Failed to clean output: %v", err)
+		}
 	}
 
-	err = pages.RenderQuotesPage(renderer, bookshelf)
+	summary, err := build(siteConfig, *workers, *includePrivate)
 	if err != nil {
-		log.Fatalf("Failed to render quotes page: %v", err)
+		log.Fatalf("Failed to build site: %v", err)
 	}
 
-	err = pages.RenderWishlistPage(renderer, bookshelf)
-	if err != nil {
-		log.Fatalf("Failed to render wishlist page: %v", err)
-	}
+	log.Printf("Pages and static files rendered successfully! (%s)", summary)
+}
 
-	err = pages.RenderBookPages(renderer, bookshelf)
-	if err != nil {
-		log.Fatalf("Failed to render book pages: %v", err)
+// loadConfig loads the configuration file, falling back to the default
+// configuration if there is no config.json.
+func loadConfig(configPath string) (config.Config, error) {
+	siteConfig, err := config.Load(configPath)
+	if errors.Is(err, fs.ErrNotExist) && configPath == defaultConfigPath {
+		log.Printf("No %s found, using the default configuration", defaultConfigPath)
+		return config.Default(), nil
 	}
 
-	log.Println("Pages and static files rendered successfully!")
+	return siteConfig, err
 }
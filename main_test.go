@@ -0,0 +1,263 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/dto"
+	"bookshelf/internal/livereload"
+	"bookshelf/internal/pages"
+	"bookshelf/internal/render"
+)
+
+func TestEmbeddedAssets(t *testing.T) {
+	// Paths that do not exist leave only the embedded templates and static files
+	paths := config.Paths{Templates: filepath.Join(t.TempDir(), "templates"), Static: filepath.Join(t.TempDir(), "static")}
+
+	layers, err := templateLayers(paths)
+	if err != nil {
+		t.Fatalf("could not load template layers: %v", err)
+	}
+	if len(layers) != 1 || layers[0].Name != "embedded" {
+		t.Fatalf("expected only the embedded template layer, got %v", layers)
+	}
+
+	static, err := staticFiles(paths)
+	if err != nil {
+		t.Fatalf("could not load static files: %v", err)
+	}
+
+	outputPath := t.TempDir()
+	renderer, err := render.New(render.TemplateRendererConfig{
+		TemplateType:           "html",
+		TemplateLayers:         layers,
+		ComponentTemplatesPath: "components",
+		PageTemplatesPath:      "pages",
+		OutputPath:             outputPath,
+		BaseTemplateName:       "base",
+		Site:                   config.Default().Site,
+	})
+	if err != nil {
+		t.Fatalf("could not create renderer from embedded templates: %v", err)
+	}
+
+	if err := renderer.CopyStaticFiles(static...); err != nil {
+		t.Fatalf("could not copy embedded static files: %v", err)
+	}
+
+	if err := pages.RenderIndexPage(renderer, &dto.Bookshelf{}); err != nil {
+		t.Fatalf("could not render index page from embedded templates: %v", err)
+	}
+
+	if _, err := renderer.Finish(); err != nil {
+		t.Fatalf("could not finish build: %v", err)
+	}
+
+	for _, fileName := range []string{"index.html", "css/style.css"} {
+		if _, err := os.Stat(filepath.Join(outputPath, fileName)); err != nil {
+			t.Errorf("expected %s to be written: %v", fileName, err)
+		}
+	}
+}
+
+func TestBuild_FailureKeepsOutput(t *testing.T) {
+	dir := t.TempDir()
+	siteConfig := config.Default()
+	siteConfig.Paths = config.Paths{
+		Data:   filepath.Join(dir, "data.json"),
+		Output: filepath.Join(dir, "dist"),
+	}
+
+	err := os.WriteFile(siteConfig.Paths.Data, []byte(`{"books": [{"id": "book-1", "title": "Book One", "status": "finished"}]}`), 0o644)
+	if err != nil {
+		t.Fatalf("could not write data file: %v", err)
+	}
+
+	if _, err := build(siteConfig, 1, false); err != nil {
+		t.Fatalf("could not build site: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(siteConfig.Paths.Output, "index.html"))
+	if err != nil {
+		t.Fatalf("expected index page to be built: %v", err)
+	}
+
+	// A page failing to render after others were written fails the whole build
+	siteConfig.Paths.Overrides = filepath.Join(dir, "overrides")
+	err = os.MkdirAll(filepath.Join(siteConfig.Paths.Overrides, "pages"), os.ModePerm)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(siteConfig.Paths.Overrides, "pages", "book.html"), []byte(`{{ define "content" }}{{ .Missing }}{{ end }}`), 0o644)
+	}
+	if err != nil {
+		t.Fatalf("could not write override: %v", err)
+	}
+
+	err = os.WriteFile(siteConfig.Paths.Data, []byte(`{"books": [{"id": "book-2", "title": "Book Two", "status": "finished"}]}`), 0o644)
+	if err != nil {
+		t.Fatalf("could not write data file: %v", err)
+	}
+
+	if _, err := build(siteConfig, 1, false); err == nil {
+		t.Fatal("expected the build to fail")
+	}
+
+	current, err := os.ReadFile(filepath.Join(siteConfig.Paths.Output, "index.html"))
+	if err != nil || string(current) != string(index) {
+		t.Errorf("expected the index page of the last build to be kept (%v)", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("could not read directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".dist") {
+			t.Errorf("expected no leftovers of the failed build, got %s", entry.Name())
+		}
+	}
+}
+
+func TestBuild_MissingData(t *testing.T) {
+	dir := t.TempDir()
+	siteConfig := config.Default()
+	siteConfig.Paths = config.Paths{
+		Data:   filepath.Join(dir, "data.json"),
+		Output: filepath.Join(dir, "dist"),
+	}
+
+	err := os.WriteFile(siteConfig.Paths.Data, []byte(`{"books": [{"id": "book-1", "title": "Book One", "status": "finished"}]}`), 0o644)
+	if err != nil {
+		t.Fatalf("could not write data file: %v", err)
+	}
+	if _, err := build(siteConfig, 1, false); err != nil {
+		t.Fatalf("could not build site: %v", err)
+	}
+
+	// A mistyped data path must not replace the site with an empty one
+	for _, data := range []string{"missing.db", "missing.json"} {
+		siteConfig.Paths.Data = filepath.Join(dir, data)
+		if _, err := build(siteConfig, 1, false); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected the build of the missing %s to fail, got %v", data, err)
+		}
+		if _, err := os.Stat(siteConfig.Paths.Data); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be created, got %v", data, err)
+		}
+	}
+
+	siteConfig.Shelves = []config.Shelf{{Name: "alice", Data: filepath.Join(dir, "alice.db")}}
+	if _, err := build(siteConfig, 1, false); err == nil {
+		t.Error("expected the build of a shelf with a missing data file to fail")
+	}
+
+	if _, err := os.Stat(filepath.Join(siteConfig.Paths.Output, "book-1.html")); err != nil {
+		t.Errorf("expected the output of the last build to be kept: %v", err)
+	}
+}
+
+const privateData = `{
+	"books": [
+		{"id": "public-book", "title": "Public Book", "authors": ["Author A"], "status": "finished", "date_added": "2025-01-01",
+			"progress": {"date_finished": "2025-02-01"}, "rating": 4,
+			"review": ["A public paragraph.", {"text": "A leaked-review paragraph.", "visibility": "private"}],
+			"quotes": ["A public quote.", {"text": "A leaked-quote.", "visibility": "draft"}]},
+		{"id": "leaked-book", "title": "Leaked Private Book", "authors": ["Leaked Author"], "genre": "leaked-genre",
+			"isbn": "leaked-isbn", "status": "finished", "date_added": "2025-01-02", "visibility": "private",
+			"progress": {"date_finished": "2025-03-01"}, "rating": 5, "quotes": ["A leaked private book quote."]},
+		{"id": "leaked-draft", "title": "Leaked Draft", "status": "wishlisted", "rank": 1, "date_added": "2025-01-03", "visibility": "draft"}
+	],
+	"collections": [
+		{"name": "Leaked Collection", "description": "Only private books", "books": ["leaked-book"]},
+		{"name": "Mixed", "description": "Public and private books", "books": ["leaked-draft", "public-book"]}
+	]
+}`
+
+func TestBuild_Private(t *testing.T) {
+	dir := t.TempDir()
+	siteConfig := config.Default()
+	siteConfig.Paths = config.Paths{
+		Data:   filepath.Join(dir, "data.json"),
+		Output: filepath.Join(dir, "dist"),
+	}
+
+	if err := os.WriteFile(siteConfig.Paths.Data, []byte(privateData), 0o644); err != nil {
+		t.Fatalf("could not write data file: %v", err)
+	}
+
+	if _, err := build(siteConfig, 1, false); err != nil {
+		t.Fatalf("could not build site: %v", err)
+	}
+
+	// Every file of the output, including the manifest, the feeds, the API
+	// documents and the sitemap
+	leaked := func(outputPath string) []string {
+		var leaks []string
+		err := filepath.WalkDir(outputPath, func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(strings.ToLower(string(content)), "leaked") {
+				leaks = append(leaks, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read output: %v", err)
+		}
+
+		return leaks
+	}
+
+	if leaks := leaked(siteConfig.Paths.Output); len(leaks) > 0 {
+		t.Errorf("expected no private data in the output, found some in %v", leaks)
+	}
+
+	content, err := os.ReadFile(filepath.Join(siteConfig.Paths.Output, "public-book.html"))
+	if err != nil || !strings.Contains(string(content), "A public quote.") {
+		t.Errorf("expected the public book to be rendered with its public quotes (%v)", err)
+	}
+
+	if _, err := build(siteConfig, 1, true); err != nil {
+		t.Fatalf("could not build preview: %v", err)
+	}
+
+	preview := filepath.Join(dir, "dist-private")
+	for _, fileName := range []string{"leaked-book.html", "leaked-draft.html"} {
+		if _, err := os.Stat(filepath.Join(preview, fileName)); err != nil {
+			t.Errorf("expected %s to be rendered in the preview: %v", fileName, err)
+		}
+	}
+	if leaks := leaked(preview); len(leaks) == 0 {
+		t.Error("expected private data in the preview")
+	}
+
+	// The preview leaves the public output alone
+	if leaks := leaked(siteConfig.Paths.Output); len(leaks) > 0 {
+		t.Errorf("expected no private data in the output after a preview, found some in %v", leaks)
+	}
+}
+
+func TestReloadKind(t *testing.T) {
+	tests := []struct {
+		changed  []string
+		expected string
+	}{
+		{[]string{"static/css/style.css"}, livereload.ReloadCSS},
+		{[]string{"static/css/style.css", "templates/base.html"}, livereload.ReloadPage},
+		{[]string{"data/data.json"}, livereload.ReloadPage},
+	}
+
+	for _, tt := range tests {
+		if kind := reloadKind(tt.changed); kind != tt.expected {
+			t.Errorf("expected %s reload for %v, got %s", tt.expected, tt.changed, kind)
+		}
+	}
+}
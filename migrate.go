@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+
+	"bookshelf/internal/storage"
+)
+
+// migrate copies the bookshelf from one storage to another, e.g. from the JSON
+// file to an SQLite database. The storage type is chosen by the extension.
+func migrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	from := flags.String("from", "data/data.json", "data file to migrate from")
+	to := flags.String("to", "data/data.db", "data file to migrate to, .db, .sqlite or .sqlite3 for SQLite")
+	force := flags.Bool("force", false, "replace the data of an existing target")
+	flags.Parse(args)
+
+	if _, err := os.Stat(*to); !errors.Is(err, fs.ErrNotExist) && !*force {
+		log.Fatalf("%s already exists, use -force to replace its data", *to)
+	}
+
+	bookshelf, err := storage.Migrate(*from, *to)
+	if err != nil {
+		log.Fatalf("Failed to migrate %s to %s: %v", *from, *to, err)
+	}
+
+	log.Printf("Migrated %d books and %d collections from %s to %s", len(bookshelf.Books), len(bookshelf.Collections), *from, *to)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"bookshelf/internal/config"
+	"bookshelf/internal/livereload"
+	"bookshelf/internal/render"
+	"bookshelf/internal/watch"
+)
+
+// serve builds the site into a temporary directory and serves it, rebuilding
+// and reloading open browsers whenever the data, templates, static files or
+// configuration change.
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "path of the site configuration file")
+	addr := flags.String("addr", "localhost:8080", "address to serve the site on")
+	interval := flags.Duration("interval", 500*time.Millisecond, "interval to check for changed files")
+	includePrivate := flags.Bool("include-private", false, "include private and draft books, reviews and quotes")
+	flags.Parse(args)
+
+	dir, err := os.MkdirTemp("", "bookshelf-serve-")
+	if err != nil {
+		log.Fatalf("Failed to create build directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "site")
+
+	siteConfig := loadServeConfig(*configPath, outputPath)
+	rebuild(siteConfig, *includePrivate)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// building keeps requests from reaching the output path while a rebuild
+	// moves it aside to put the new output in its place
+	var building sync.RWMutex
+
+	broker := livereload.New()
+	watcher := watch.New(watchedPaths(*configPath, siteConfig)...)
+	go watcher.Run(ctx, *interval, func(changed []string) {
+		log.Printf("Changed: %v", changed)
+
+		// The configuration may change the paths to watch, which are only
+		// picked up on restart
+		building.Lock()
+		summary, err := rebuild(loadServeConfig(*configPath, outputPath), *includePrivate)
+		building.Unlock()
+		if err != nil {
+			broker.Error(err)
+			return
+		}
+
+		if summary.Written > 0 || summary.Removed > 0 {
+			broker.Reload(reloadKind(changed))
+		}
+	})
+
+	site := broker.Handler(buildOutputPath(siteConfig.Paths, *includePrivate))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The event stream stays open, it must not hold up rebuilds
+		if r.URL.Path != livereload.EventsPath {
+			building.RLock()
+			defer building.RUnlock()
+		}
+
+		site.ServeHTTP(w, r)
+	})
+
+	server := &http.Server{Addr: *addr, Handler: handler}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	log.Printf("Serving the site on http://%s", *addr)
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to serve site: %v", err)
+	}
+}
+
+func loadServeConfig(configPath, outputPath string) config.Config {
+	siteConfig, err := loadConfig(configPath)
+	if err != nil {
+		log.Printf("Failed to load configuration, using the default: %v", err)
+		siteConfig = config.Default()
+	}
+	siteConfig.Paths.Output = outputPath
+
+	return siteConfig
+}
+
+// rebuild builds the whole site again, every page is rendered. Only the files
+// whose content changed are written, so the browsers only reload if a change
+// affects the output.
+func rebuild(siteConfig config.Config, includePrivate bool) (render.BuildSummary, error) {
+	summary, err := build(siteConfig, 0, includePrivate)
+	if err != nil {
+		log.Printf("Failed to build site: %v", err)
+		return summary, err
+	}
+
+	log.Printf("Site rebuilt (%s)", summary)
+	return summary, nil
+}
+
+func watchedPaths(configPath string, siteConfig config.Config) []string {
+	paths := siteConfig.Paths
+	watched := []string{configPath, paths.Data, paths.Templates, paths.Static}
+	for _, path := range []string{paths.Theme, paths.Overrides} {
+		if path != "" {
+			watched = append(watched, path)
+		}
+	}
+	for _, shelf := range siteConfig.Shelves {
+		watched = append(watched, shelf.Data)
+	}
+
+	return watched
+}
+
+// reloadKind only reloads the stylesheets if nothing but stylesheets changed.
+func reloadKind(changed []string) string {
+	for _, path := range changed {
+		if filepath.Ext(path) != ".css" {
+			return livereload.ReloadPage
+		}
+	}
+
+	return livereload.ReloadCSS
+}
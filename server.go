@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"bookshelf/internal/admin"
+	"bookshelf/internal/render"
+	"bookshelf/internal/rest"
+	"bookshelf/internal/server"
+)
+
+// runServer serves the site rendered from the data file on request, reloading
+// the data whenever the file changes, until it is interrupted or terminated.
+func runServer(args []string) {
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "path of the site configuration file")
+	addr := flags.String("addr", ":8080", "address to serve the site on")
+	interval := flags.Duration("interval", 2*time.Second, "interval to check the data file for changes")
+	shutdownTimeout := flags.Duration("shutdown-timeout", 10*time.Second, "time to wait for open requests on shutdown")
+	flags.Parse(args)
+
+	siteConfig, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The admin area and the REST API edit a single data file
+	if len(siteConfig.Shelves) > 0 {
+		log.Fatal("Server mode serves a single shelf, build the site or use the development server for several shelves")
+	}
+
+	layers, err := templateLayers(siteConfig.Paths)
+	if err != nil {
+		log.Fatalf("Failed to load templates: %v", err)
+	}
+
+	static, err := staticFiles(siteConfig.Paths)
+	if err != nil {
+		log.Fatalf("Failed to load static files: %v", err)
+	}
+
+	siteServer, err := server.New(server.Config{
+		Site: siteConfig,
+		Renderer: render.TemplateRendererConfig{
+			TemplateType:           "html",
+			TemplateLayers:         layers,
+			ComponentTemplatesPath: "components",
+			PageTemplatesPath:      "pages",
+			BaseTemplateName:       "base",
+		},
+		Static: static,
+	})
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+	defer siteServer.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go siteServer.Watch(ctx, *interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", siteServer)
+
+	if siteConfig.Admin.PasswordHash != "" {
+		adminHandler, err := admin.New(admin.Config{
+			PasswordHash: siteConfig.Admin.PasswordHash,
+			Store:        siteServer,
+		})
+		if err != nil {
+			log.Fatalf("Failed to load admin templates: %v", err)
+		}
+
+		mux.Handle("/admin/", adminHandler)
+		log.Printf("Serving the admin area on %s/admin/", *addr)
+	}
+
+	if len(siteConfig.REST.TokenHashes) > 0 {
+		restHandler := rest.New(rest.Config{
+			TokenHashes: siteConfig.REST.TokenHashes,
+			Store:       siteServer,
+			Static:      siteServer,
+		})
+
+		for _, pattern := range rest.Patterns {
+			mux.Handle(pattern, restHandler)
+		}
+		log.Printf("Serving the REST API on %s/api/", *addr)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down, waiting for open requests")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		shutdown <- httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving the site on %s", *addr)
+	err = httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to serve site: %v", err)
+	}
+
+	err = <-shutdown
+	if err != nil {
+		log.Fatalf("Failed to shut down gracefully: %v", err)
+	}
+
+	log.Println("Server stopped")
+}
+
+// hashPassword reads a password from the standard input and prints its hash
+// for the admin password_hash in the configuration.
+func hashPassword(args []string) {
+	flags := flag.NewFlagSet("hash-password", flag.ExitOnError)
+	flags.Parse(args)
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		log.Fatal("The password must not be empty")
+	}
+
+	hash, err := admin.HashPassword(password)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+
+	fmt.Println(hash)
+}
+
+// generateToken prints a random token for the REST API and its hash for the
+// rest token_hashes in the configuration.
+func generateToken(args []string) {
+	flags := flag.NewFlagSet("generate-token", flag.ExitOnError)
+	flags.Parse(args)
+
+	token, hash, err := rest.GenerateToken()
+	if err != nil {
+		log.Fatalf("Failed to generate token: %v", err)
+	}
+
+	fmt.Printf("Token: %s\nHash:  %s\n", token, hash)
+}
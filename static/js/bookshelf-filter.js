@@ -0,0 +1,57 @@
+// Sorts and filters the books of the bookshelf page. Every book knows its
+// position in each sort order, so nothing has to be compared here. Without
+// JavaScript the books stay in the configured order and the filter stays
+// hidden.
+(() => {
+  const form = document.getElementById('bookshelf-filter');
+  if (!form) {
+    return;
+  }
+
+  const sections = document.querySelectorAll('.bookshelf-section');
+
+  function matches(item, filter) {
+    const rating = parseFloat(item.dataset.rating) || 0;
+    const tags = item.dataset.tags ? item.dataset.tags.split(',') : [];
+
+    return (!filter.genre || item.dataset.genre === filter.genre)
+      && (!filter.language || item.dataset.language === filter.language)
+      && (!filter.tag || tags.includes(filter.tag))
+      && rating >= filter.minRating
+      && rating <= filter.maxRating;
+  }
+
+  function update() {
+    const value = (name) => (form.elements[name] ? form.elements[name].value : '');
+    const order = value('sort');
+    const filter = {
+      genre: value('genre'),
+      language: value('language'),
+      tag: value('tag'),
+      minRating: parseFloat(value('min-rating')) || 0,
+      maxRating: value('max-rating') === '' ? Infinity : parseFloat(value('max-rating')),
+    };
+
+    sections.forEach((section) => {
+      const grid = section.querySelector('.books-grid');
+      const items = Array.from(grid.querySelectorAll('.bookshelf-item'));
+      const position = (item) => JSON.parse(item.dataset.positions)[order] ?? 0;
+
+      items.sort((a, b) => position(a) - position(b)).forEach((item) => {
+        item.hidden = !matches(item, filter);
+        grid.appendChild(item);
+      });
+
+      const empty = section.querySelector('.empty-set');
+      if (empty) {
+        empty.hidden = items.length === 0 || items.some((item) => !item.hidden);
+      }
+    });
+  }
+
+  form.hidden = false;
+  form.addEventListener('submit', (e) => e.preventDefault(), false);
+  form.addEventListener('input', update, false);
+  form.addEventListener('change', update, false);
+  update();
+})();
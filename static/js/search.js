@@ -0,0 +1,121 @@
+// Searches the books with the search.json written by the build, which is
+// loaded once the search box is used. Words of the query shorter than three
+// letters have to start a word of the book, longer ones have to share all
+// their trigrams with it. Without JavaScript the search box stays hidden.
+(() => {
+  const search = document.getElementById('search');
+  if (!search) {
+    return;
+  }
+
+  const input = search.querySelector('input');
+  const results = search.querySelector('.search-results');
+  const maxResults = 10;
+  let index;
+
+  function words(text) {
+    return text.toLowerCase().split(/[^\p{L}\p{N}]+/u).filter((word) => word !== '');
+  }
+
+  function queryTokens(word) {
+    const runes = Array.from(word);
+    if (runes.length < 3) {
+      return [word];
+    }
+
+    const trigrams = [];
+    for (let i = 0; i + 3 <= runes.length; i++) {
+      trigrams.push(runes.slice(i, i + 3).join(''));
+    }
+    return trigrams;
+  }
+
+  function find(query) {
+    const terms = words(query);
+    let matches = null;
+
+    terms.forEach((word) => {
+      queryTokens(word).forEach((token) => {
+        const books = new Set(index.tokens[token] || []);
+        matches = matches === null ? books : new Set([...matches].filter((book) => books.has(book)));
+      });
+    });
+
+    // Books with words of their title starting with the query come first
+    const rank = (book) => {
+      const title = words(book.title);
+      return terms.filter((term) => title.some((word) => word.startsWith(term))).length;
+    };
+
+    return [...(matches || [])]
+      .map((i) => index.books[i])
+      .sort((a, b) => rank(b) - rank(a) || a.title.localeCompare(b.title))
+      .slice(0, maxResults);
+  }
+
+  function show(books) {
+    results.replaceChildren(...books.map((book) => {
+      const link = document.createElement('a');
+      link.href = `${book.id}.html`;
+      link.textContent = book.title;
+
+      if (book.authors) {
+        const authors = document.createElement('span');
+        authors.className = 'authors';
+        authors.textContent = book.authors.join(', ');
+        link.append(authors);
+      }
+
+      const item = document.createElement('li');
+      item.append(link);
+      return item;
+    }));
+    results.hidden = books.length === 0;
+  }
+
+  async function load() {
+    if (!index) {
+      const response = await fetch(search.dataset.index);
+      index = await response.json();
+    }
+    return index;
+  }
+
+  input.addEventListener('focus', () => load().catch(() => {}), { once: true });
+
+  input.addEventListener('input', async () => {
+    const query = input.value.trim();
+    if (query === '') {
+      show([]);
+      return;
+    }
+
+    try {
+      await load();
+    } catch {
+      return;
+    }
+    // Results of an outdated query are dropped
+    if (input.value.trim() === query) {
+      show(find(query));
+    }
+  });
+
+  input.addEventListener('keydown', (e) => {
+    const first = results.querySelector('a');
+    if (e.key === 'Enter' && first) {
+      window.location.href = first.href;
+    } else if (e.key === 'Escape') {
+      input.value = '';
+      show([]);
+    }
+  });
+
+  search.addEventListener('focusout', (e) => {
+    if (!search.contains(e.relatedTarget)) {
+      results.hidden = true;
+    }
+  });
+
+  search.hidden = false;
+})();
\ No newline at end of file
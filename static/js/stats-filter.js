@@ -0,0 +1,20 @@
+// Switches between the pre-rendered stats panels. Without JavaScript only the
+// all time stats are shown and the filter stays hidden.
+(() => {
+  const filter = document.getElementById('stats-filter');
+  if (!filter) {
+    return;
+  }
+
+  const panels = document.querySelectorAll('.stats-panel');
+
+  function showStats(value) {
+    panels.forEach((panel) => {
+      panel.hidden = panel.dataset.stats !== value;
+    });
+  }
+
+  filter.closest('.stats-filter').hidden = false;
+  filter.addEventListener('change', (e) => showStats(e.target.value), false);
+  showStats(filter.value);
+})();